@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"go-initial-project/entity"
+	"go-initial-project/tenant"
 	"log"
 
 	"gorm.io/driver/postgres"
@@ -21,15 +22,43 @@ func ConnectDB() *gorm.DB {
 		AppConfig.DB.TimeZone,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		// Unique ihlalleri gorm.ErrDuplicatedKey olarak döner (409 için).
+		TranslateError: true,
+	})
 	if err != nil {
 		log.Fatal("Failed to connect database:", err)
 	}
+	// Tenant'a ait tabloların sorguları context'teki organizasyonla sınırlanır.
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		log.Fatal("Failed to register tenant plugin:", err)
+	}
 
 	sqlDB, _ := db.DB()
 	sqlDB.Exec("SET TIME ZONE ?", AppConfig.DB.TimeZone)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.Activity{})
+	err = db.AutoMigrate(
+		&entity.User{},
+		&entity.Activity{},
+		&entity.RefreshToken{},
+		&entity.RevokedToken{},
+		&entity.Permission{},
+		&entity.Role{},
+		&entity.PasswordResetToken{},
+		&entity.PhoneVerification{},
+		&entity.RecoveryCode{},
+		&entity.LoginThrottle{},
+		&entity.APIKey{},
+		&entity.Identity{},
+		&entity.Session{},
+		&entity.OAuthClient{},
+		&entity.OAuthAuthorizationCode{},
+		&entity.OAuthConsent{},
+		&entity.WebAuthnCredential{},
+		&entity.Organization{},
+		&entity.Membership{},
+		&entity.Invitation{},
+	)
 	if err != nil {
 		return nil
 	}
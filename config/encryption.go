@@ -0,0 +1,21 @@
+package config
+
+import (
+	"errors"
+	"go-initial-project/encryption"
+	"log"
+)
+
+// NewCipher APP_ENCRYPTION_KEY ile veritabanı alan şifrelemesini kurar.
+// Anahtar değişirse mevcut şifreli değerler (TOTP secret'ları) okunamaz hale gelir.
+func NewCipher() (*encryption.Cipher, error) {
+	key := AppConfig.App.EncryptionKey
+	if key == "" {
+		if AppConfig.App.Env == "production" {
+			return nil, errors.New("APP_ENCRYPTION_KEY is required in production")
+		}
+		log.Println("⚠️ APP_ENCRYPTION_KEY not set, deriving it from JWT_SECRET")
+		key = AppConfig.JWT.Secret
+	}
+	return encryption.New([]byte(key))
+}
@@ -3,14 +3,21 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type EnvConfig struct {
 	App struct {
-		Port string
-		Env  string
+		Name          string
+		Port          string
+		Env           string
+		AdminEmail    string
+		FrontendURL   string
+		EncryptionKey string
 	}
 	DB struct {
 		Host     string
@@ -22,8 +29,114 @@ type EnvConfig struct {
 		TimeZone string
 	}
 	JWT struct {
-		Secret string
+		Secret          string
+		Algorithm       string
+		KeyID           string
+		KeysDir         string
+		PreviousSecrets string
+		AccessTTL       time.Duration
+		RefreshTTL      time.Duration
+		// Issuer ve Audience her token'a yazılır ve doğrulamada zorunludur.
+		Issuer   string
+		Audience string
+		// Leeway sunucular arası saat farkı için exp/nbf/iat kontrollerine eklenen tolerans.
+		Leeway time.Duration
+		// RevocationStore iptal edilen token'ların tutulacağı yer: "db" veya "memory"
+		RevocationStore string
 	}
+	Auth struct {
+		PasswordResetTTL           time.Duration
+		EmailVerificationTTL       time.Duration
+		VerificationResendInterval time.Duration
+		// RequireVerifiedEmail true ise e-postasını doğrulamamış kullanıcılar giriş yapamaz.
+		RequireVerifiedEmail bool
+		MFAChallengeTTL      time.Duration
+		// Brute-force koruması
+		FailureWindow       time.Duration
+		LockoutThreshold    int
+		IPLockoutThreshold  int
+		LockoutDuration     time.Duration
+		ProgressiveDelayMax time.Duration
+		// ImpersonationTTL admin'in kullanıcı adına aldığı token'ın ömrü.
+		ImpersonationTTL time.Duration
+		// Magic link (şifresiz giriş)
+		MagicLinkTTL        time.Duration
+		MagicLinkWindow     time.Duration
+		MagicLinkEmailLimit int
+		MagicLinkIPLimit    int
+		// Telefon doğrulama kodu (SMS)
+		PhoneVerificationTTL            time.Duration
+		PhoneVerificationResendInterval time.Duration
+		PhoneVerificationMaxAttempts    int
+		// InvitationTTL organizasyon davet linkinin geçerlilik süresi.
+		InvitationTTL time.Duration
+	}
+	Password struct {
+		// Hasher yeni hash'ler için algoritma: "bcrypt" veya "argon2id".
+		// Diğer algoritmayla üretilmiş hash'ler doğrulanır ve login'de yenilenir.
+		Hasher            string
+		BcryptCost        int
+		Argon2Memory      int // KiB
+		Argon2Iterations  int
+		Argon2Parallelism int
+		Argon2SaltLength  int
+		Argon2KeyLength   int
+		// Şifre politikası
+		MinLength      int
+		MaxLength      int
+		RequireUpper   bool
+		RequireLower   bool
+		RequireDigit   bool
+		RequireSymbol  bool
+		RejectPersonal bool
+		// BreachedFile SHA-1'e göre sıralı sızdırılmış şifre listesi; boşsa kontrol yapılmaz.
+		BreachedFile string
+	}
+	Mail struct {
+		// Driver: "log", "file" veya "smtp"
+		Driver   string
+		From     string
+		Dir      string
+		SMTPHost string
+		SMTPPort string
+		SMTPUser string
+		SMTPPass string
+	}
+	SMS struct {
+		// Driver: şimdilik sadece "log"; yeni sağlayıcılar sms.Sender'ı uygular.
+		Driver string
+		From   string
+	}
+	OAuth struct {
+		// AccessTTL OAuth client'larına verilen access token'ların ömrü.
+		AccessTTL time.Duration
+		CodeTTL   time.Duration
+	}
+	WebAuthn struct {
+		// RPID passkey'lerin bağlı olduğu alan adı (şema ve port olmadan).
+		RPID          string
+		RPDisplayName string
+		// RPOrigins tarayıcının ceremony'yi başlatabileceği origin'ler.
+		RPOrigins []string
+		// Timeout kullanıcının authenticator'la işlemi tamamlaması için süre.
+		Timeout time.Duration
+	}
+	OIDC struct {
+		Providers []OIDCProviderConfig
+		// FlowTTL login'e başlayıp callback'e dönene kadar izin verilen süre.
+		FlowTTL time.Duration
+	}
+}
+
+// OIDCProviderConfig OIDC_PROVIDERS içindeki her sağlayıcı için
+// OIDC_<NAME>_* değişkenlerinden okunur.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
 }
 
 var AppConfig *EnvConfig
@@ -35,8 +148,12 @@ func LoadEnv() {
 
 	AppConfig = &EnvConfig{}
 
+	AppConfig.App.Name = getEnv("APP_NAME", "go-initial-project")
 	AppConfig.App.Port = getEnv("APP_PORT", "8080")
 	AppConfig.App.Env = getEnv("APP_ENV", "development")
+	AppConfig.App.AdminEmail = getEnv("ADMIN_EMAIL", "")
+	AppConfig.App.FrontendURL = getEnv("APP_FRONTEND_URL", "http://localhost:3000")
+	AppConfig.App.EncryptionKey = getEnv("APP_ENCRYPTION_KEY", "")
 
 	AppConfig.DB.Host = getEnv("DB_HOST", "localhost")
 	AppConfig.DB.Port = getEnv("DB_PORT", "5432")
@@ -47,6 +164,101 @@ func LoadEnv() {
 	AppConfig.DB.TimeZone = getEnv("DB_TIMEZONE", "Europe/Istanbul")
 
 	AppConfig.JWT.Secret = getEnv("JWT_SECRET", "super-secret-key")
+	AppConfig.JWT.Algorithm = getEnv("JWT_ALGORITHM", "HS256")
+	AppConfig.JWT.KeyID = getEnv("JWT_KEY_ID", "primary")
+	AppConfig.JWT.KeysDir = getEnv("JWT_KEYS_DIR", "")
+	AppConfig.JWT.PreviousSecrets = getEnv("JWT_PREVIOUS_SECRETS", "")
+	AppConfig.JWT.AccessTTL = getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute)
+	AppConfig.JWT.RefreshTTL = getEnvDuration("JWT_REFRESH_TTL", 30*24*time.Hour)
+	AppConfig.JWT.Issuer = getEnv("JWT_ISSUER", AppConfig.App.Name)
+	AppConfig.JWT.Audience = getEnv("JWT_AUDIENCE", AppConfig.App.Name)
+	AppConfig.JWT.Leeway = getEnvDuration("JWT_LEEWAY", 30*time.Second)
+	AppConfig.JWT.RevocationStore = getEnv("JWT_REVOCATION_STORE", "db")
+
+	AppConfig.Auth.PasswordResetTTL = getEnvDuration("AUTH_PASSWORD_RESET_TTL", time.Hour)
+	AppConfig.Auth.EmailVerificationTTL = getEnvDuration("AUTH_EMAIL_VERIFICATION_TTL", 24*time.Hour)
+	AppConfig.Auth.VerificationResendInterval = getEnvDuration("AUTH_VERIFICATION_RESEND_INTERVAL", time.Minute)
+	AppConfig.Auth.RequireVerifiedEmail = getEnvBool("AUTH_REQUIRE_VERIFIED_EMAIL", false)
+	AppConfig.Auth.MFAChallengeTTL = getEnvDuration("AUTH_MFA_CHALLENGE_TTL", 5*time.Minute)
+	AppConfig.Auth.FailureWindow = getEnvDuration("AUTH_FAILURE_WINDOW", 15*time.Minute)
+	AppConfig.Auth.LockoutThreshold = getEnvInt("AUTH_LOCKOUT_THRESHOLD", 10)
+	AppConfig.Auth.IPLockoutThreshold = getEnvInt("AUTH_IP_LOCKOUT_THRESHOLD", 50)
+	AppConfig.Auth.LockoutDuration = getEnvDuration("AUTH_LOCKOUT_DURATION", 15*time.Minute)
+	AppConfig.Auth.ProgressiveDelayMax = getEnvDuration("AUTH_PROGRESSIVE_DELAY_MAX", 30*time.Second)
+	AppConfig.Auth.ImpersonationTTL = getEnvDuration("AUTH_IMPERSONATION_TTL", 15*time.Minute)
+	AppConfig.Auth.MagicLinkTTL = getEnvDuration("AUTH_MAGIC_LINK_TTL", 15*time.Minute)
+	AppConfig.Auth.MagicLinkWindow = getEnvDuration("AUTH_MAGIC_LINK_WINDOW", time.Hour)
+	AppConfig.Auth.MagicLinkEmailLimit = getEnvInt("AUTH_MAGIC_LINK_EMAIL_LIMIT", 3)
+	AppConfig.Auth.MagicLinkIPLimit = getEnvInt("AUTH_MAGIC_LINK_IP_LIMIT", 20)
+	AppConfig.Auth.PhoneVerificationTTL = getEnvDuration("AUTH_PHONE_VERIFICATION_TTL", 10*time.Minute)
+	AppConfig.Auth.PhoneVerificationResendInterval = getEnvDuration("AUTH_PHONE_VERIFICATION_RESEND_INTERVAL", time.Minute)
+	AppConfig.Auth.PhoneVerificationMaxAttempts = getEnvInt("AUTH_PHONE_VERIFICATION_MAX_ATTEMPTS", 5)
+	AppConfig.Auth.InvitationTTL = getEnvDuration("AUTH_INVITATION_TTL", 7*24*time.Hour)
+
+	AppConfig.Password.Hasher = getEnv("PASSWORD_HASHER", "bcrypt")
+	AppConfig.Password.BcryptCost = getEnvInt("PASSWORD_BCRYPT_COST", 10)
+	AppConfig.Password.Argon2Memory = getEnvInt("PASSWORD_ARGON2_MEMORY", 64*1024)
+	AppConfig.Password.Argon2Iterations = getEnvInt("PASSWORD_ARGON2_ITERATIONS", 3)
+	AppConfig.Password.Argon2Parallelism = getEnvInt("PASSWORD_ARGON2_PARALLELISM", 2)
+	AppConfig.Password.Argon2SaltLength = getEnvInt("PASSWORD_ARGON2_SALT_LENGTH", 16)
+	AppConfig.Password.Argon2KeyLength = getEnvInt("PASSWORD_ARGON2_KEY_LENGTH", 32)
+	AppConfig.Password.MinLength = getEnvInt("PASSWORD_MIN_LENGTH", 8)
+	AppConfig.Password.MaxLength = getEnvInt("PASSWORD_MAX_LENGTH", 72)
+	AppConfig.Password.RequireUpper = getEnvBool("PASSWORD_REQUIRE_UPPER", false)
+	AppConfig.Password.RequireLower = getEnvBool("PASSWORD_REQUIRE_LOWER", false)
+	AppConfig.Password.RequireDigit = getEnvBool("PASSWORD_REQUIRE_DIGIT", false)
+	AppConfig.Password.RequireSymbol = getEnvBool("PASSWORD_REQUIRE_SYMBOL", false)
+	AppConfig.Password.RejectPersonal = getEnvBool("PASSWORD_REJECT_PERSONAL", true)
+	AppConfig.Password.BreachedFile = getEnv("PASSWORD_BREACHED_FILE", "")
+
+	AppConfig.Mail.Driver = getEnv("MAIL_DRIVER", "log")
+	AppConfig.Mail.From = getEnv("MAIL_FROM", "no-reply@localhost")
+	AppConfig.Mail.Dir = getEnv("MAIL_DIR", "storage/mail")
+	AppConfig.Mail.SMTPHost = getEnv("SMTP_HOST", "localhost")
+	AppConfig.Mail.SMTPPort = getEnv("SMTP_PORT", "587")
+	AppConfig.Mail.SMTPUser = getEnv("SMTP_USER", "")
+	AppConfig.Mail.SMTPPass = getEnv("SMTP_PASS", "")
+
+	AppConfig.SMS.Driver = getEnv("SMS_DRIVER", "log")
+	AppConfig.SMS.From = getEnv("SMS_FROM", AppConfig.App.Name)
+
+	AppConfig.OAuth.AccessTTL = getEnvDuration("OAUTH_ACCESS_TTL", time.Hour)
+	AppConfig.OAuth.CodeTTL = getEnvDuration("OAUTH_CODE_TTL", time.Minute)
+
+	AppConfig.WebAuthn.RPID = getEnv("WEBAUTHN_RP_ID", "localhost")
+	AppConfig.WebAuthn.RPDisplayName = getEnv("WEBAUTHN_RP_NAME", AppConfig.App.Name)
+	AppConfig.WebAuthn.RPOrigins = strings.Split(getEnv("WEBAUTHN_RP_ORIGINS", AppConfig.App.FrontendURL), ",")
+	AppConfig.WebAuthn.Timeout = getEnvDuration("WEBAUTHN_TIMEOUT", 5*time.Minute)
+
+	AppConfig.OIDC.Providers = loadOIDCProviders()
+	AppConfig.OIDC.FlowTTL = getEnvDuration("OIDC_FLOW_TTL", 10*time.Minute)
+}
+
+// loadOIDCProviders OIDC_PROVIDERS=company,google gibi bir listeyi okur.
+// Issuer veya client id'si eksik sağlayıcılar atlanır.
+func loadOIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(getEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", AppConfig.App.FrontendURL+"/oidc/callback/"+name),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			log.Printf("⚠️ OIDC provider %s is missing %sISSUER or %sCLIENT_ID, skipping", name, prefix, prefix)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
 }
 
 func getEnv(key, fallback string) string {
@@ -55,3 +267,42 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("⚠️ invalid int for %s: %q, using %d", key, value, fallback)
+		return fallback
+	}
+	return i
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("⚠️ invalid bool for %s: %q, using %t", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("⚠️ invalid duration for %s: %q, using %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
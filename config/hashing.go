@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"go-initial-project/hashing"
+)
+
+// NewPasswordHasher PASSWORD_HASHER ile seçilen algoritmayı tercih eden,
+// diğerini sadece doğrulama ve yenileme için tanıyan bir hashing.Manager kurar.
+func NewPasswordHasher() (*hashing.Manager, error) {
+	cfg := AppConfig.Password
+
+	bcryptHasher, err := hashing.NewBcrypt(cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+	argonHasher, err := hashing.NewArgon2id(
+		uint32(cfg.Argon2Memory),
+		uint32(cfg.Argon2Iterations),
+		uint8(cfg.Argon2Parallelism),
+		uint32(cfg.Argon2SaltLength),
+		uint32(cfg.Argon2KeyLength),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Hasher {
+	case "bcrypt":
+		return hashing.NewManager(bcryptHasher, argonHasher), nil
+	case "argon2id":
+		return hashing.NewManager(argonHasher, bcryptHasher), nil
+	default:
+		return nil, fmt.Errorf("unknown PASSWORD_HASHER %q", cfg.Hasher)
+	}
+}
@@ -1,5 +1,100 @@
 package config
 
+import (
+	"fmt"
+	"go-initial-project/keyring"
+	"log"
+	"strings"
+	"time"
+)
+
 func JWTSecret() []byte {
 	return []byte(AppConfig.JWT.Secret)
 }
+
+func AccessTokenTTL() time.Duration {
+	return AppConfig.JWT.AccessTTL
+}
+
+func RefreshTokenTTL() time.Duration {
+	return AppConfig.JWT.RefreshTTL
+}
+
+// NewKeyring env ayarlarından imzalama/doğrulama anahtarlarını kurar.
+//
+// HS256: JWT_SECRET, JWT_KEY_ID ile aktif anahtardır; JWT_PREVIOUS_SECRETS
+// ("kid:secret,...") rotasyon sırasında hâlâ kabul edilen eski secret'lardır.
+// RS256/EdDSA: JWT_KEYS_DIR altındaki PEM dosyaları yüklenir ve JWT_KEY_ID aktif olur.
+func NewKeyring() (*keyring.Keyring, error) {
+	cfg := AppConfig.JWT
+	ring := keyring.New()
+
+	for _, pair := range strings.Split(cfg.PreviousSecrets, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("JWT_PREVIOUS_SECRETS: expected kid:secret, got %q", pair)
+		}
+		key, err := keyring.NewHMACKey(kid, []byte(secret))
+		if err != nil {
+			return nil, err
+		}
+		if err := ring.Add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	switch cfg.Algorithm {
+	case keyring.HS256:
+		key, err := keyring.NewHMACKey(cfg.KeyID, JWTSecret())
+		if err != nil {
+			return nil, err
+		}
+		if err := ring.Add(key); err != nil {
+			return nil, err
+		}
+
+	case keyring.RS256, keyring.EdDSA:
+		if cfg.KeysDir != "" {
+			keys, err := keyring.LoadDir(cfg.KeysDir)
+			if err != nil {
+				return nil, err
+			}
+			for _, key := range keys {
+				if err := ring.Add(key); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := ensureDevKey(ring, cfg.KeyID, cfg.Algorithm); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT_ALGORITHM %q", cfg.Algorithm)
+	}
+
+	if err := ring.SetActive(cfg.KeyID); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// ensureDevKey aktif anahtar dizinde yoksa geliştirme ortamında geçici bir anahtar üretir.
+func ensureDevKey(ring *keyring.Keyring, kid, algorithm string) error {
+	if err := ring.SetActive(kid); err == nil {
+		return nil
+	}
+	if AppConfig.App.Env == "production" {
+		return fmt.Errorf("signing key %q not found in JWT_KEYS_DIR", kid)
+	}
+	log.Printf("⚠️ signing key %q not found, generating an ephemeral %s key", kid, algorithm)
+	key, err := keyring.Generate(kid, algorithm)
+	if err != nil {
+		return err
+	}
+	return ring.Add(key)
+}
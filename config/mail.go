@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"go-initial-project/mailer"
+)
+
+// NewMailer MAIL_DRIVER ayarına göre mailer sürücüsünü oluşturur.
+func NewMailer() (mailer.Mailer, error) {
+	cfg := AppConfig.Mail
+	switch cfg.Driver {
+	case "log":
+		return mailer.NewLogMailer(cfg.From), nil
+	case "file":
+		return mailer.NewFileMailer(cfg.From, cfg.Dir)
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.From, cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass), nil
+	default:
+		return nil, fmt.Errorf("unsupported MAIL_DRIVER %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import "go-initial-project/passwordpolicy"
+
+// NewPasswordPolicy PASSWORD_* ayarlarından şifre politikasını kurar.
+// PASSWORD_BREACHED_FILE verilmişse dosya açılır ve süreç boyunca açık kalır.
+func NewPasswordPolicy() (*passwordpolicy.Policy, error) {
+	cfg := AppConfig.Password
+	policy := &passwordpolicy.Policy{
+		MinLength:      cfg.MinLength,
+		MaxLength:      cfg.MaxLength,
+		RequireUpper:   cfg.RequireUpper,
+		RequireLower:   cfg.RequireLower,
+		RequireDigit:   cfg.RequireDigit,
+		RequireSymbol:  cfg.RequireSymbol,
+		RejectPersonal: cfg.RejectPersonal,
+	}
+	if cfg.BreachedFile != "" {
+		breached, err := passwordpolicy.OpenSHA1File(cfg.BreachedFile)
+		if err != nil {
+			return nil, err
+		}
+		policy.Breached = breached
+	}
+	return policy, nil
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"fmt"
+	"go-initial-project/sms"
+)
+
+// NewSMSSender SMS_DRIVER'a göre gönderim sürücüsünü oluşturur.
+func NewSMSSender() (sms.Sender, error) {
+	cfg := AppConfig.SMS
+	switch cfg.Driver {
+	case "log":
+		return sms.NewLogSender(cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unsupported SMS_DRIVER %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// NewWebAuthn WEBAUTHN_* ayarlarıyla relying party'yi kurar. Passkey'ler
+// discoverable (resident) oluşturulur ve kullanıcı doğrulaması istenir.
+func NewWebAuthn() (*webauthn.WebAuthn, error) {
+	cfg := AppConfig.WebAuthn
+
+	origins := make([]string, 0, len(cfg.RPOrigins))
+	for _, origin := range cfg.RPOrigins {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.Timeout, TimeoutUVD: cfg.Timeout}
+	return webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+		Timeouts: webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+}
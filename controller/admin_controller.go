@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	adminres "go-initial-project/responses/admin"
+	"go-initial-project/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AdminController struct {
+	tokenService         *service.TokenService
+	impersonationService *service.ImpersonationService
+}
+
+func NewAdminController(tokenService *service.TokenService, impersonationService *service.ImpersonationService) *AdminController {
+	return &AdminController{tokenService: tokenService, impersonationService: impersonationService}
+}
+
+func (adc *AdminController) RegisterRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin", middleware.AuthRequired(adc.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation())
+	{
+		admin.POST("/users/:id/impersonate", middleware.RequirePermission(entity.PermUsersImpersonate), adc.Impersonate)
+	}
+}
+
+// Impersonate godoc
+// @Summary Impersonate a user
+// @Description Issue a short-lived access token for the user, carrying the admin as the "act" claim. No refresh token is issued. Every request made with it is logged with the admin as actor, and sensitive actions (password change, 2FA, API keys, sessions) are blocked.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} admin.ImpersonationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /admin/users/{id}/impersonate [post]
+func (adc *AdminController) Impersonate(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	actorID := ctx.GetString("user_id")
+	result, err := adc.impersonationService.Start(actorID, id, clientInfo(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		case errors.Is(err, service.ErrImpersonateSelf):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrImpersonateAdmin):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not impersonate user"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, adminres.ImpersonationResponse{
+		Token:          result.Token,
+		ExpiresIn:      result.ExpiresIn,
+		ImpersonatorID: actorID,
+		User:           newUserResponse(result.User),
+	})
+}
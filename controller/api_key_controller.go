@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	authreq "go-initial-project/requests/auth"
+	authres "go-initial-project/responses/auth"
+	"go-initial-project/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyController struct {
+	tokenService  *service.TokenService
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyController(tokenService *service.TokenService, apiKeyService *service.APIKeyService) *APIKeyController {
+	return &APIKeyController{tokenService: tokenService, apiKeyService: apiKeyService}
+}
+
+func (kc *APIKeyController) RegisterRoutes(r *gin.RouterGroup) {
+	keys := r.Group("/auth/api-keys", middleware.AuthRequired(kc.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation())
+	{
+		keys.GET("", kc.List)
+		keys.POST("", middleware.RequireVerifiedEmail(), kc.Create)
+		keys.DELETE("/:id", kc.Revoke)
+	}
+}
+
+// List godoc
+// @Summary List API keys
+// @Description List the current user's API keys. Secrets are never returned.
+// @Tags api-keys
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} auth.APIKeyResponse
+// @Failure 401 {object} map[string]string
+// @Router /auth/api-keys [get]
+func (kc *APIKeyController) List(ctx *gin.Context) {
+	keys, err := kc.apiKeyService.List(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list api keys"})
+		return
+	}
+
+	res := make([]authres.APIKeyResponse, len(keys))
+	for i := range keys {
+		res[i] = newAPIKeyResponse(&keys[i])
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Create godoc
+// @Summary Create API key
+// @Description Create an API key for machine-to-machine access. Scopes must be a subset of the caller's permissions. The key is shown only once.
+// @Tags api-keys
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body auth.CreateAPIKeyRequest true "Key name, scopes and optional expiry"
+// @Success 201 {object} auth.CreatedAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/api-keys [post]
+func (kc *APIKeyController) Create(ctx *gin.Context) {
+	var req authreq.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, raw, err := kc.apiKeyService.Create(ctx.GetString("user_id"), req.Name, req.Scopes, req.ExpiresAt, clientInfo(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAPIKeyScope):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrAPIKeyExpiry):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not create api key"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, authres.CreatedAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(key),
+		Key:            raw,
+	})
+}
+
+// Revoke godoc
+// @Summary Revoke API key
+// @Description Revoke one of the current user's API keys
+// @Tags api-keys
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/api-keys/{id} [delete]
+func (kc *APIKeyController) Revoke(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	ok, err := kc.apiKeyService.Revoke(ctx.GetString("user_id"), id, clientInfo(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke api key"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "api key not found"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func newAPIKeyResponse(key *entity.APIKey) authres.APIKeyResponse {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return authres.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -1,42 +1,96 @@
 package controller
 
 import (
+	"errors"
 	"go-initial-project/config"
 	"go-initial-project/entity"
 	"go-initial-project/middleware"
+	"go-initial-project/passwordpolicy"
 	authreq "go-initial-project/requests/auth"
 	authres "go-initial-project/responses/auth"
 	userres "go-initial-project/responses/user"
 	"go-initial-project/service"
 
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthController struct {
-	userService *service.UserService
+	userService              *service.UserService
+	tokenService             *service.TokenService
+	passwordResetService     *service.PasswordResetService
+	emailVerificationService *service.EmailVerificationService
+	mfaService               *service.MFAService
+	loginThrottleService     *service.LoginThrottleService
+	sessionService           *service.SessionService
+	passwordService          *service.PasswordService
+	webauthnService          *service.WebAuthnService
+	invitationService        *service.InvitationService
 }
 
-func NewAuthController(userService *service.UserService) *AuthController {
-	return &AuthController{userService: userService}
+func NewAuthController(
+	userService *service.UserService,
+	tokenService *service.TokenService,
+	passwordResetService *service.PasswordResetService,
+	emailVerificationService *service.EmailVerificationService,
+	mfaService *service.MFAService,
+	loginThrottleService *service.LoginThrottleService,
+	sessionService *service.SessionService,
+	passwordService *service.PasswordService,
+	webauthnService *service.WebAuthnService,
+	invitationService *service.InvitationService,
+) *AuthController {
+	return &AuthController{
+		userService:              userService,
+		tokenService:             tokenService,
+		passwordResetService:     passwordResetService,
+		emailVerificationService: emailVerificationService,
+		mfaService:               mfaService,
+		loginThrottleService:     loginThrottleService,
+		sessionService:           sessionService,
+		passwordService:          passwordService,
+		webauthnService:          webauthnService,
+		invitationService:        invitationService,
+	}
 }
 
 func (ac *AuthController) RegisterRoutes(r *gin.RouterGroup) {
 	auth := r.Group("/auth")
 	{
 		auth.POST("/login", ac.Login)
+		auth.POST("/login/mfa", ac.LoginMFA)
 		auth.POST("/register", ac.Register)
-		auth.GET("/me", middleware.AuthRequired(), ac.Me)
+		auth.POST("/refresh", ac.Refresh)
+		auth.POST("/verify-email", ac.VerifyEmail)
+		auth.POST("/verify-email/resend", ac.ResendVerification)
+		auth.POST("/password/forgot", ac.ForgotPassword)
+		auth.POST("/password/reset", ac.ResetPassword)
+		auth.POST("/password/change", middleware.AuthRequired(ac.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation(), ac.ChangePassword)
+		auth.POST("/logout", middleware.AuthRequired(ac.tokenService), middleware.RejectAPIKey(), ac.Logout)
+		auth.GET("/me", middleware.AuthRequired(ac.tokenService), ac.Me)
+
+		// Passkey (WebAuthn) ceremony'leri
+		auth.POST("/webauthn/login/begin", ac.BeginPasskeyLogin)
+		auth.POST("/webauthn/login/finish", ac.FinishPasskeyLogin)
+		auth.POST("/login/mfa/webauthn/begin", ac.BeginPasskeyMFA)
+		auth.POST("/login/mfa/webauthn", ac.LoginPasskeyMFA)
+
+		passkeys := auth.Group("/webauthn", middleware.AuthRequired(ac.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation())
+		passkeys.POST("/register/begin", ac.BeginPasskeyRegistration)
+		passkeys.POST("/register/finish", ac.FinishPasskeyRegistration)
+		passkeys.GET("/credentials", ac.ListPasskeys)
+		passkeys.DELETE("/credentials/:id", ac.DeletePasskey)
 	}
 }
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user with email and password, return JWT token
+// @Description Authenticate user with email and password, return access and refresh tokens
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -45,6 +99,7 @@ func (ac *AuthController) RegisterRoutes(r *gin.RouterGroup) {
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Router /auth/login [post]
 func (ac *AuthController) Login(ctx *gin.Context) {
 	var req authreq.LoginRequest
@@ -57,44 +112,80 @@ func (ac *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	user, err := ac.userService.FindByEmail(req.Email)
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+	client := clientInfo(ctx)
+	keys := []string{service.EmailThrottleKey(req.Email), service.IPThrottleKey(client.IP)}
+	if err := ac.loginThrottleService.Check(keys...); err != nil {
+		respondThrottled(ctx, err)
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+	user, err := ac.userService.Authenticate(req.Email, req.Password)
+	if err != nil {
+		if err := ac.loginThrottleService.RecordFailure(client, keys...); err != nil {
+			log.Printf("⚠️ failed to record login failure: %v", err)
+		}
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
+	if err := ac.loginThrottleService.Reset(service.EmailThrottleKey(req.Email)); err != nil {
+		log.Printf("⚠️ failed to reset login throttle: %v", err)
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.ID,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-	})
-	tokenString, _ := token.SignedString(config.JWTSecret())
+	respondWithLogin(ctx, ac.tokenService, ac.mfaService, user)
+}
 
-	res := authres.AuthResponse{
-		Token: tokenString,
-		User: userres.UserResponse{
-			ID:        user.ID,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Email:     user.Email,
-		},
+// LoginMFA godoc
+// @Summary Complete two-factor login
+// @Description Exchange the MFA challenge token from login and a TOTP or recovery code for access and refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body auth.LoginMFARequest true "Challenge and code"
+// @Success 200 {object} auth.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/login/mfa [post]
+func (ac *AuthController) LoginMFA(ctx *gin.Context) {
+	var req authreq.LoginMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
 	}
-	ctx.JSON(http.StatusOK, res)
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := ac.mfaService.CompleteChallenge(req.MFAToken, req.Code, clientInfo(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPurposeToken):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+		case errors.Is(err, service.ErrInvalidMFACode), errors.Is(err, service.ErrMFANotEnrolled):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": service.ErrInvalidMFACode.Error()})
+		case errors.Is(err, service.ErrLoginThrottled):
+			respondThrottled(ctx, err)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not complete login"})
+		}
+		return
+	}
+
+	ac.respondWithTokens(ctx, http.StatusOK, user)
 }
 
 // Register godoc
 // @Summary Register user
-// @Description Create a new user account and return JWT token
+// @Description Create a new user account and return access and refresh tokens. With invitation_token the user joins the inviting organization; the e-mail must match the invitation and counts as verified.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param data body auth.RegisterRequest true "Register data"
 // @Success 201 {object} auth.AuthResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /auth/register [post]
@@ -108,37 +199,348 @@ func (ac *AuthController) Register(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
 	}
+
+	var invitation *entity.Invitation
+	if req.InvitationToken != "" {
+		pending, err := ac.invitationService.Pending(ctx.Request.Context(), req.InvitationToken)
+		if err == nil && !pending.SentTo(req.Email) {
+			err = service.ErrInvitationEmailMismatch
+		}
+		if err != nil {
+			respondInvitationError(ctx, err)
+			return
+		}
+		invitation = pending
+	}
+
 	user := entity.User{
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Email:     req.Email,
-		Password:  req.Password,
+	}
+	// Davet linki bu adrese gönderildiğinden e-posta doğrulanmış sayılır.
+	if invitation != nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := ac.userService.ValidatePassword(&user, req.Password); err != nil {
+		if !respondPasswordPolicy(ctx, "password", err) {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not create user"})
+		}
+		return
+	}
+	if err := ac.userService.SetPassword(&user, req.Password); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not create user"})
+		return
 	}
 
-	createdUser, err := ac.userService.Create(user)
+	var createdUser entity.User
+	if invitation != nil {
+		// Kullanıcı ve üyelik birlikte oluşur; davet bu arada geçersizleştiyse hesap açılmaz.
+		registered, err := ac.invitationService.Register(ctx.Request.Context(), user, invitation, clientInfo(ctx))
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidInvitation) {
+				respondInvitationError(ctx, err)
+			} else {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not create user"})
+			}
+			return
+		}
+		createdUser = *registered
+	} else {
+		created, err := ac.userService.Create(user)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not create user"})
+			return
+		}
+		createdUser = created
+	}
+
+	if err := ac.emailVerificationService.Send(&createdUser); err != nil {
+		log.Println("❌ Verification mail err:", err)
+	}
+
+	// Doğrulama zorunluysa token verilmez; kullanıcı linke tıkladıktan sonra giriş yapar.
+	if config.AppConfig.Auth.RequireVerifiedEmail {
+		ctx.JSON(http.StatusCreated, gin.H{
+			"message": "verification e-mail sent",
+			"user":    newUserResponse(&createdUser),
+		})
+		return
+	}
+
+	ac.respondWithTokens(ctx, http.StatusCreated, &createdUser)
+}
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Rotate refresh token and return a new access/refresh token pair. Reusing a rotated refresh token revokes the whole token family.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body auth.RefreshRequest true "Refresh token"
+// @Success 200 {object} auth.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/refresh [post]
+func (ac *AuthController) Refresh(ctx *gin.Context) {
+	var req authreq.RefreshRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	pair, user, err := ac.tokenService.Refresh(req.RefreshToken, clientInfo(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not create user"})
+		switch {
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token reuse detected, please log in again"})
+		case errors.Is(err, service.ErrInvalidRefreshToken):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not refresh token"})
+		}
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, createdUser)
+	ctx.JSON(http.StatusOK, newAuthResponse(pair, user))
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.ID,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-	})
-	tokenString, _ := token.SignedString(config.JWTSecret())
+// Logout godoc
+// @Summary Logout user
+// @Description End the current session: revoke the access token and the session's refresh tokens. A refresh token from another session can be passed to revoke its family too.
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Param data body auth.LogoutRequest false "Refresh token to revoke"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/logout [post]
+func (ac *AuthController) Logout(ctx *gin.Context) {
+	var req authreq.LogoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+			return
+		}
+	}
 
-	res := authres.AuthResponse{
-		Token: tokenString,
-		User: userres.UserResponse{
-			ID:        user.ID,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Email:     user.Email,
-		},
+	claims, _ := ctx.MustGet("claims").(*service.AccessClaims)
+	if claims == nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "token cannot be revoked"})
+		return
 	}
-	ctx.JSON(http.StatusCreated, res)
+
+	if err := ac.tokenService.RevokeAccessToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke token"})
+		return
+	}
+	// Oturum kapanınca refresh token ailesi de iptal edilir.
+	if sessionID := ctx.GetString("session_id"); sessionID != "" {
+		if _, err := ac.sessionService.Revoke(ctx.GetString("user_id"), sessionID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke session"})
+			return
+		}
+	}
+	if req.RefreshToken != "" {
+		if err := ac.tokenService.RevokeRefreshToken(req.RefreshToken); err != nil && !errors.Is(err, service.ErrInvalidRefreshToken) {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke refresh token"})
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// VerifyEmail godoc
+// @Summary Verify e-mail address
+// @Description Confirm the e-mail address with the signed token from the verification link
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body auth.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} user.UserResponse
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/verify-email [post]
+func (ac *AuthController) VerifyEmail(ctx *gin.Context) {
+	var req authreq.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := ac.emailVerificationService.Verify(req.Token, clientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPurposeToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired verification link"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not verify e-mail"})
+		return
+	}
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// ResendVerification godoc
+// @Summary Resend verification e-mail
+// @Description Send the verification link again. Limited to one e-mail per AUTH_VERIFICATION_RESEND_INTERVAL.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body auth.ResendVerificationRequest true "E-mail"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/verify-email/resend [post]
+func (ac *AuthController) ResendVerification(ctx *gin.Context) {
+	var req authreq.ResendVerificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ac.emailVerificationService.Resend(req.Email); err != nil {
+		if errors.Is(err, service.ErrVerificationThrottled) {
+			interval := config.AppConfig.Auth.VerificationResendInterval
+			ctx.Header("Retry-After", strconv.Itoa(int(interval.Seconds())))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println("❌ Verification mail err:", err)
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "if the e-mail is registered and not verified, a new link has been sent"})
+}
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Send a single-use password reset link. Always returns 202 so that registered e-mails cannot be discovered.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body auth.ForgotPasswordRequest true "E-mail"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/password/forgot [post]
+func (ac *AuthController) ForgotPassword(ctx *gin.Context) {
+	var req authreq.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ac.passwordResetService.RequestReset(req.Email, clientInfo(ctx)); err != nil {
+		log.Println("❌ Password reset request err:", err)
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "if the e-mail is registered, a reset link has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with a reset token. All existing sessions of the user are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body auth.ResetPasswordRequest true "Reset data"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/password/reset [post]
+func (ac *AuthController) ResetPassword(ctx *gin.Context) {
+	var req authreq.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ac.passwordResetService.Reset(req.Token, req.Password, clientInfo(ctx)); err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if respondPasswordPolicy(ctx, "password", err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not reset password"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the password of the current user. Requires the current password; all other sessions are logged out.
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body auth.ChangePasswordRequest true "Current and new password"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/password/change [post]
+func (ac *AuthController) ChangePassword(ctx *gin.Context) {
+	var req authreq.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	err := ac.passwordService.Change(user, req.CurrentPassword, req.NewPassword, ctx.GetString("session_id"), clientInfo(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWrongPassword):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPasswordUnchanged), errors.Is(err, service.ErrNoPasswordSet):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrLoginThrottled):
+			respondThrottled(ctx, err)
+		default:
+			if !respondPasswordPolicy(ctx, "new_password", err) {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not change password"})
+			}
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
 }
 
 // Me godoc
@@ -146,27 +548,398 @@ func (ac *AuthController) Register(ctx *gin.Context) {
 // @Description Return current authenticated user info
 // @Tags auth
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Success 200 {object} user.UserResponse
 // @Failure 401 {object} map[string]string
 // @Router /auth/me [get]
 func (ac *AuthController) Me(ctx *gin.Context) {
-	userID, exists := ctx.Get("user_id")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	res := newUserResponse(user)
+	res.ImpersonatedBy = ctx.GetString("actor_id")
+	ctx.JSON(http.StatusOK, res)
+}
+
+// BeginPasskeyRegistration godoc
+// @Summary Start passkey registration
+// @Description Return the options for navigator.credentials.create() and an opaque session to send back to the finish step
+// @Tags webauthn
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} auth.WebAuthnOptionsResponse
+// @Failure 401 {object} map[string]string
+// @Router /auth/webauthn/register/begin [post]
+func (ac *AuthController) BeginPasskeyRegistration(ctx *gin.Context) {
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	options, session, err := ac.webauthnService.BeginRegistration(user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not start passkey registration"})
+		return
+	}
+	ctx.JSON(http.StatusOK, authres.WebAuthnOptionsResponse{Options: options, Session: session})
+}
+
+// FinishPasskeyRegistration godoc
+// @Summary Finish passkey registration
+// @Description Verify the authenticator's attestation and store the passkey
+// @Tags webauthn
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body auth.WebAuthnFinishRegistrationRequest true "Session, optional name and the credential from navigator.credentials.create()"
+// @Success 201 {object} auth.WebAuthnCredentialResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/webauthn/register/finish [post]
+func (ac *AuthController) FinishPasskeyRegistration(ctx *gin.Context) {
+	var req authreq.WebAuthnFinishRegistrationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	credential, err := ac.webauthnService.FinishRegistration(user, req.Session, req.Name, req.Credential, clientInfo(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidWebAuthnSession), errors.Is(err, service.ErrPasskeyFailed):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not register passkey"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, newWebAuthnCredentialResponse(credential))
+}
+
+// ListPasskeys godoc
+// @Summary List passkeys
+// @Description List the current user's passkeys
+// @Tags webauthn
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} auth.WebAuthnCredentialResponse
+// @Failure 401 {object} map[string]string
+// @Router /auth/webauthn/credentials [get]
+func (ac *AuthController) ListPasskeys(ctx *gin.Context) {
+	credentials, err := ac.webauthnService.List(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list passkeys"})
+		return
+	}
+
+	res := make([]authres.WebAuthnCredentialResponse, len(credentials))
+	for i := range credentials {
+		res[i] = newWebAuthnCredentialResponse(&credentials[i])
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeletePasskey godoc
+// @Summary Delete passkey
+// @Description Remove one of the current user's passkeys
+// @Tags webauthn
+// @Security BearerAuth
+// @Param id path string true "Passkey ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/webauthn/credentials/{id} [delete]
+func (ac *AuthController) DeletePasskey(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	ok, err := ac.webauthnService.Delete(ctx.GetString("user_id"), id, clientInfo(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete passkey"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "passkey not found"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// BeginPasskeyLogin godoc
+// @Summary Start passkey login
+// @Description Return the options for navigator.credentials.get(). No e-mail is needed; the browser offers the passkeys saved for this site.
+// @Tags webauthn
+// @Produce json
+// @Success 200 {object} auth.WebAuthnOptionsResponse
+// @Router /auth/webauthn/login/begin [post]
+func (ac *AuthController) BeginPasskeyLogin(ctx *gin.Context) {
+	options, session, err := ac.webauthnService.BeginLogin()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not start passkey login"})
+		return
+	}
+	ctx.JSON(http.StatusOK, authres.WebAuthnOptionsResponse{Options: options, Session: session})
+}
+
+// FinishPasskeyLogin godoc
+// @Summary Finish passkey login
+// @Description Verify the assertion and return access and refresh tokens. Passkeys require user verification, so no second factor is asked.
+// @Tags webauthn
+// @Accept json
+// @Produce json
+// @Param data body auth.WebAuthnFinishLoginRequest true "Session and the credential from navigator.credentials.get()"
+// @Success 200 {object} auth.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/webauthn/login/finish [post]
+func (ac *AuthController) FinishPasskeyLogin(ctx *gin.Context) {
+	var req authreq.WebAuthnFinishLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	client := clientInfo(ctx)
+	ipKey := service.IPThrottleKey(client.IP)
+	if err := ac.loginThrottleService.Check(ipKey); err != nil {
+		respondThrottled(ctx, err)
+		return
+	}
+
+	user, err := ac.webauthnService.FinishLogin(req.Session, req.Credential, client)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidWebAuthnSession):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPasskeyFailed):
+			if err := ac.loginThrottleService.RecordFailure(client, ipKey); err != nil {
+				log.Printf("⚠️ failed to record login failure: %v", err)
+			}
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not complete login"})
+		}
+		return
+	}
+
+	if config.AppConfig.Auth.RequireVerifiedEmail && !user.EmailVerified() {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "email not verified"})
+		return
+	}
+	ac.respondWithTokens(ctx, http.StatusOK, user)
+}
+
+// BeginPasskeyMFA godoc
+// @Summary Start two-factor login with a passkey
+// @Description Return assertion options for the user's passkeys, as an alternative to a TOTP code in the second login step
+// @Tags webauthn
+// @Accept json
+// @Produce json
+// @Param data body auth.WebAuthnBeginMFARequest true "MFA challenge from login"
+// @Success 200 {object} auth.WebAuthnOptionsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/login/mfa/webauthn/begin [post]
+func (ac *AuthController) BeginPasskeyMFA(ctx *gin.Context) {
+	var req authreq.WebAuthnBeginMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	options, session, err := ac.mfaService.BeginWebAuthnChallenge(req.MFAToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPurposeToken):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+		case errors.Is(err, service.ErrMFANotEnrolled):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "no passkey registered"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not start passkey login"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, authres.WebAuthnOptionsResponse{Options: options, Session: session})
+}
+
+// LoginPasskeyMFA godoc
+// @Summary Complete two-factor login with a passkey
+// @Description Exchange the MFA challenge token and a passkey assertion for access and refresh tokens
+// @Tags webauthn
+// @Accept json
+// @Produce json
+// @Param data body auth.WebAuthnLoginMFARequest true "Challenge, session and the credential from navigator.credentials.get()"
+// @Success 200 {object} auth.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/login/mfa/webauthn [post]
+func (ac *AuthController) LoginPasskeyMFA(ctx *gin.Context) {
+	var req authreq.WebAuthnLoginMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := ac.mfaService.CompleteWebAuthnChallenge(req.MFAToken, req.Session, req.Credential, clientInfo(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPurposeToken):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+		case errors.Is(err, service.ErrInvalidWebAuthnSession):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidMFACode):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": service.ErrPasskeyFailed.Error()})
+		case errors.Is(err, service.ErrLoginThrottled):
+			respondThrottled(ctx, err)
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not complete login"})
+		}
+		return
+	}
+
+	ac.respondWithTokens(ctx, http.StatusOK, user)
+}
+
+func (ac *AuthController) respondWithTokens(ctx *gin.Context, status int, user *entity.User) {
+	pair, err := ac.tokenService.IssueTokens(user, clientInfo(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not issue token"})
+		return
+	}
+	ctx.JSON(status, newAuthResponse(pair, user))
+}
+
+// respondWithLogin kimliği doğrulanmış kullanıcı için login'i tamamlar:
+// e-posta doğrulama şartını kontrol eder, MFA açıksa token yerine challenge döner.
+func respondWithLogin(ctx *gin.Context, tokenService *service.TokenService, mfaService *service.MFAService, user *entity.User) {
+	if config.AppConfig.Auth.RequireVerifiedEmail && !user.EmailVerified() {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "email not verified"})
 		return
 	}
 
-	user, err := ac.userService.First(map[string]interface{}{"id": userID.(string)})
+	if user.MFAEnabled() {
+		challenge, err := mfaService.IssueChallenge(user)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not issue token"})
+			return
+		}
+		methods, err := mfaService.Methods(user)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not issue token"})
+			return
+		}
+		ctx.JSON(http.StatusOK, authres.MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    challenge,
+			ExpiresIn:   int64(config.AppConfig.Auth.MFAChallengeTTL.Seconds()),
+			Methods:     methods,
+		})
+		return
+	}
+
+	pair, err := tokenService.IssueTokens(user, clientInfo(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not issue token"})
 		return
 	}
+	ctx.JSON(http.StatusOK, newAuthResponse(pair, user))
+}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"id":         user.ID,
-		"first_name": user.FirstName,
-		"last_name":  user.LastName,
-		"email":      user.Email,
+func newAuthResponse(pair *service.TokenPair, user *entity.User) authres.AuthResponse {
+	return authres.AuthResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+		User:         newUserResponse(user),
+	}
+}
+
+func newUserResponse(user *entity.User) userres.UserResponse {
+	return userres.UserResponse{
+		ID:            user.ID,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified(),
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerified(),
+	}
+}
+
+// respondThrottled 429 ve Retry-After header'ı yazar.
+func respondThrottled(ctx *gin.Context, err error) {
+	var throttled *service.ThrottledError
+	if errors.As(err, &throttled) {
+		seconds := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(seconds))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not complete login"})
+}
+
+// respondPasswordPolicy şifre politikası ihlalini alan bazlı 422 olarak yazar:
+// {"error": "...", "fields": {"password": ["must contain a digit", ...]}}.
+// Hata politika ihlali değilse false döner ve cevap yazılmaz.
+func respondPasswordPolicy(ctx *gin.Context, field string, err error) bool {
+	var policyErr *passwordpolicy.Error
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+	ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":  "password does not meet the policy",
+		"fields": gin.H{field: policyErr.Problems},
 	})
+	return true
+}
+
+func newWebAuthnCredentialResponse(credential *entity.WebAuthnCredential) authres.WebAuthnCredentialResponse {
+	transports := credential.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	return authres.WebAuthnCredentialResponse{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		Transports: transports,
+		Synced:     credential.BackupState,
+		LastUsedAt: credential.LastUsedAt,
+		CreatedAt:  credential.CreatedAt,
+	}
+}
+
+func clientInfo(ctx *gin.Context) service.ClientInfo {
+	return service.ClientInfo{IP: ctx.ClientIP(), UserAgent: ctx.Request.UserAgent()}
 }
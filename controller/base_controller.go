@@ -1,134 +1,225 @@
 package controller
 
 import (
+	"errors"
 	"go-initial-project/service"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-type BaseController[T any] struct {
-	service service.BaseServiceInterface[T]
+// Mapper bir resource'un HTTP şekli ile entity'si arasındaki dönüşümler.
+// Entity hiçbir zaman doğrudan bind edilmez; id, zaman damgaları, şifre gibi
+// alanlar istekten atanamaz. Request tiplerinin Validate() error metodu varsa
+// bind'dan sonra çağrılır.
+type Mapper[T, C, U, R any] struct {
+	// NewEntity create isteğinden kaydedilecek entity'i üretir.
+	NewEntity func(req *C) T
+	// ApplyUpdate update isteğini mevcut entity'e uygular ve değişen kolonları döner.
+	ApplyUpdate func(item *T, req *U) map[string]interface{}
+	// ToResponse entity'nin cevaptaki şekli.
+	ToResponse func(item *T) R
 }
 
-func NewBaseController[T any](service service.BaseServiceInterface[T]) *BaseController[T] {
-	return &BaseController[T]{service: service}
+// BaseController T entity'si için CRUD handler'ları. ID anahtar tipi, C
+// create, U update isteği, R cevap tipidir. Path'teki id parseID ile
+// çevrilir; geçersiz id'ler servise ulaşmadan 400 döner.
+type BaseController[T any, ID comparable, C, U, R any] struct {
+	service service.BaseServiceInterface[T, ID]
+	parseID IDParser[ID]
+	mapper  Mapper[T, C, U, R]
 }
 
-func (c *BaseController[T]) GetAll(ctx *gin.Context) {
-	items, err := c.service.GetAll()
+func NewBaseController[T any, ID comparable, C, U, R any](service service.BaseServiceInterface[T, ID], parseID IDParser[ID], mapper Mapper[T, C, U, R]) *BaseController[T, ID, C, U, R] {
+	return &BaseController[T, ID, C, U, R]{service: service, parseID: parseID, mapper: mapper}
+}
+
+func (c *BaseController[T, ID, C, U, R]) GetAll(ctx *gin.Context) {
+	items, err := c.scoped(ctx).GetAll()
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, items)
+	ctx.JSON(http.StatusOK, c.toResponses(items))
 }
 
-func (c *BaseController[T]) GetByID(ctx *gin.Context) {
-	id, _ := strconv.Atoi(ctx.Param("id"))
-	item, err := c.service.GetByID(uint(id))
+func (c *BaseController[T, ID, C, U, R]) GetByID(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", c.parseID)
+	if !ok {
+		return
+	}
+	item, err := c.scoped(ctx).GetByID(id)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		respondLookupError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, item)
+	ctx.JSON(http.StatusOK, c.mapper.ToResponse(&item))
 }
 
-func (c *BaseController[T]) Create(ctx *gin.Context) {
-	var item T
-	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (c *BaseController[T, ID, C, U, R]) Create(ctx *gin.Context) {
+	var req C
+	if !bindRequest(ctx, &req) {
 		return
 	}
-	created, err := c.service.Create(item)
+	created, err := c.scoped(ctx).Create(c.mapper.NewEntity(&req))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWriteError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusCreated, created)
+	ctx.JSON(http.StatusCreated, c.mapper.ToResponse(&created))
 }
 
-func (c *BaseController[T]) Update(ctx *gin.Context) {
-	var item T
-	if err := ctx.ShouldBindJSON(&item); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// Update sadece istekte gelen ve değişen kolonları yazar; entity'nin geri
+// kalanı (şifre hash'i vb.) olduğu gibi kalır.
+func (c *BaseController[T, ID, C, U, R]) Update(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", c.parseID)
+	if !ok {
+		return
+	}
+	var req U
+	if !bindRequest(ctx, &req) {
 		return
 	}
-	updated, err := c.service.Update(item)
+	svc := c.scoped(ctx)
+	item, err := svc.GetByID(id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondLookupError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, updated)
+	if values := c.mapper.ApplyUpdate(&item, &req); len(values) > 0 {
+		if err := svc.UpdateWhere(map[string]interface{}{"id": id}, values); err != nil {
+			respondWriteError(ctx, err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, c.mapper.ToResponse(&item))
 }
 
-func (c *BaseController[T]) Delete(ctx *gin.Context) {
-	id, _ := strconv.Atoi(ctx.Param("id"))
+func (c *BaseController[T, ID, C, U, R]) Delete(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", c.parseID)
+	if !ok {
+		return
+	}
 	var item T
-	if err := c.service.Delete(uint(id), item); err != nil {
+	if err := c.scoped(ctx).Delete(id, item); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	ctx.Status(http.StatusNoContent)
 }
 
-func (c *BaseController[T]) HardDelete(ctx *gin.Context) {
-	id, _ := strconv.Atoi(ctx.Param("id"))
+func (c *BaseController[T, ID, C, U, R]) HardDelete(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", c.parseID)
+	if !ok {
+		return
+	}
 	var item T
-	if err := c.service.HardDelete(uint(id), item); err != nil {
+	if err := c.scoped(ctx).HardDelete(id, item); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	ctx.Status(http.StatusNoContent)
 }
 
-func (c *BaseController[T]) Paginate(ctx *gin.Context) {
+func (c *BaseController[T, ID, C, U, R]) Paginate(ctx *gin.Context) {
 	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
-	items, total, err := c.service.Paginate(offset, limit)
+	items, total, err := c.scoped(ctx).Paginate(offset, limit)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"total": total, "data": items})
+	ctx.JSON(http.StatusOK, gin.H{"total": total, "data": c.toResponses(items)})
 }
 
-func (c *BaseController[T]) Search(ctx *gin.Context) {
+func (c *BaseController[T, ID, C, U, R]) Search(ctx *gin.Context) {
 	field := ctx.Query("field")
 	keyword := ctx.Query("keyword")
-	items, err := c.service.Search(field, keyword)
+	items, err := c.scoped(ctx).Search(field, keyword)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, items)
+	ctx.JSON(http.StatusOK, c.toResponses(items))
 }
 
-func (c *BaseController[T]) FindWithTrashed(ctx *gin.Context) {
-	items, err := c.service.FindWithTrashed()
+func (c *BaseController[T, ID, C, U, R]) FindWithTrashed(ctx *gin.Context) {
+	items, err := c.scoped(ctx).FindWithTrashed()
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, items)
+	ctx.JSON(http.StatusOK, c.toResponses(items))
 }
 
-func (c *BaseController[T]) OnlyTrashed(ctx *gin.Context) {
-	items, err := c.service.OnlyTrashed()
+func (c *BaseController[T, ID, C, U, R]) OnlyTrashed(ctx *gin.Context) {
+	items, err := c.scoped(ctx).OnlyTrashed()
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, items)
+	ctx.JSON(http.StatusOK, c.toResponses(items))
 }
 
-func (c *BaseController[T]) Restore(ctx *gin.Context) {
-	id, _ := strconv.Atoi(ctx.Param("id"))
+func (c *BaseController[T, ID, C, U, R]) Restore(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", c.parseID)
+	if !ok {
+		return
+	}
 	var item T
-	if err := c.service.Restore(uint(id), item); err != nil {
+	if err := c.scoped(ctx).Restore(id, item); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "restored"})
 }
+
+// scoped servisi isteğin context'ine bağlar; tenant'a ait entity'ler aktif
+// tenant'la sınırlanır.
+func (c *BaseController[T, ID, C, U, R]) scoped(ctx *gin.Context) service.BaseServiceInterface[T, ID] {
+	return c.service.WithContext(ctx.Request.Context())
+}
+
+func (c *BaseController[T, ID, C, U, R]) toResponses(items []T) []R {
+	res := make([]R, len(items))
+	for i := range items {
+		res[i] = c.mapper.ToResponse(&items[i])
+	}
+	return res
+}
+
+// bindRequest JSON'u req'e bind eder ve Validate() varsa çağırır. Hata
+// durumunda cevabı yazar ve false döner.
+func bindRequest(ctx *gin.Context, req any) bool {
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return false
+	}
+	if v, ok := req.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return false
+		}
+	}
+	return true
+}
+
+// respondLookupError kayıt bulunamadıysa 404, diğer hatalarda 500 yazar.
+func respondLookupError(ctx *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// respondWriteError create/update hatasını yazar; unique ihlali 409 olur.
+func respondWriteError(ctx *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "already exists"})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
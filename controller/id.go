@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidID = errors.New("invalid id")
+
+// IDParser path'ten gelen ham id'yi entity'nin anahtar tipine çevirir.
+type IDParser[ID comparable] func(raw string) (ID, error)
+
+// ParseUUID uuid anahtarlı entity'ler için; id küçük harfli kanonik
+// biçime çevrilir.
+func ParseUUID(raw string) (string, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return "", ErrInvalidID
+	}
+	return id.String(), nil
+}
+
+// ParseUintID auto increment anahtarlı entity'ler için; sıfır geçersizdir.
+func ParseUintID(raw string) (uint, error) {
+	id, err := strconv.ParseUint(raw, 10, strconv.IntSize)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidID
+	}
+	return uint(id), nil
+}
+
+// pathID name path parametresini parse eder. Geçersizse 400 yazar ve false döner.
+func pathID[ID comparable](ctx *gin.Context, name string, parse IDParser[ID]) (ID, bool) {
+	id, err := parse(ctx.Param(name))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return id, false
+	}
+	return id, true
+}
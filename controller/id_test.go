@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"go-initial-project/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseUUID(t *testing.T) {
+	valid := map[string]string{
+		"3f2504e0-4f89-41d3-9a0c-0305e82c3301":          "3f2504e0-4f89-41d3-9a0c-0305e82c3301",
+		"3F2504E0-4F89-41D3-9A0C-0305E82C3301":          "3f2504e0-4f89-41d3-9a0c-0305e82c3301",
+		"urn:uuid:3f2504e0-4f89-41d3-9a0c-0305e82c3301": "3f2504e0-4f89-41d3-9a0c-0305e82c3301",
+	}
+	for raw, want := range valid {
+		if got, err := ParseUUID(raw); err != nil || got != want {
+			t.Errorf("ParseUUID(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"", "1", "not-a-uuid", "3f2504e0-4f89-41d3-9a0c-0305e82c330", "3f2504e0-4f89-41d3-9a0c-0305e82c3301x", "' OR 1=1 --"} {
+		if _, err := ParseUUID(raw); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ParseUUID(%q) err = %v, want ErrInvalidID", raw, err)
+		}
+	}
+}
+
+func TestParseUintID(t *testing.T) {
+	valid := map[string]uint{"1": 1, "42": 42, "007": 7}
+	for raw, want := range valid {
+		if got, err := ParseUintID(raw); err != nil || got != want {
+			t.Errorf("ParseUintID(%q) = %d, %v; want %d", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"", "0", "-1", "+1", "1.5", "abc", "1e3", " 1", "99999999999999999999999"} {
+		if _, err := ParseUintID(raw); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ParseUintID(%q) err = %v, want ErrInvalidID", raw, err)
+		}
+	}
+}
+
+type testItem struct{ ID uint }
+type testRequest struct{}
+
+// recordingService id'leri kaydeden BaseServiceInterface; diğer metotlar çağrılırsa panic eder.
+type recordingService[ID comparable] struct {
+	service.BaseServiceInterface[testItem, ID]
+	ids []ID
+}
+
+func (s *recordingService[ID]) WithContext(context.Context) service.BaseServiceInterface[testItem, ID] {
+	return s
+}
+
+func (s *recordingService[ID]) GetByID(id ID) (testItem, error) {
+	s.ids = append(s.ids, id)
+	return testItem{}, nil
+}
+
+func (s *recordingService[ID]) Delete(id ID, _ testItem) error {
+	s.ids = append(s.ids, id)
+	return nil
+}
+
+func (s *recordingService[ID]) HardDelete(id ID, _ testItem) error {
+	s.ids = append(s.ids, id)
+	return nil
+}
+
+func (s *recordingService[ID]) Restore(id ID, _ testItem) error {
+	s.ids = append(s.ids, id)
+	return nil
+}
+
+var testMapper = Mapper[testItem, testRequest, testRequest, testItem]{
+	NewEntity:   func(*testRequest) testItem { return testItem{} },
+	ApplyUpdate: func(*testItem, *testRequest) map[string]interface{} { return nil },
+	ToResponse:  func(item *testItem) testItem { return *item },
+}
+
+func newIDRouter[ID comparable](svc *recordingService[ID], parse IDParser[ID]) *gin.Engine {
+	c := NewBaseController(service.BaseServiceInterface[testItem, ID](svc), parse, testMapper)
+	r := gin.New()
+	r.GET("/items/:id", c.GetByID)
+	r.PUT("/items/:id", c.Update)
+	r.DELETE("/items/:id", c.Delete)
+	r.DELETE("/items/:id/hard", c.HardDelete)
+	r.POST("/items/:id/restore", c.Restore)
+	return r
+}
+
+// idRoutes path'inde id olan uçlar; istek "/items/<id><suffix>" adresine atılır.
+var idRoutes = []struct {
+	method, suffix string
+}{
+	{http.MethodGet, ""},
+	{http.MethodPut, ""},
+	{http.MethodDelete, ""},
+	{http.MethodDelete, "/hard"},
+	{http.MethodPost, "/restore"},
+}
+
+// assertIDRejected her uca id ile istek atar ve 400 "invalid id" bekler.
+func assertIDRejected(t *testing.T, r *gin.Engine, id string) {
+	t.Helper()
+	for _, route := range idRoutes {
+		w := serveID(r, route.method, "/items/"+id+route.suffix)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"invalid id"`) {
+			t.Errorf("%s /items/%s%s: %d %s, want 400 invalid id", route.method, id, route.suffix, w.Code, w.Body.String())
+		}
+	}
+}
+
+func serveID(r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestBaseControllerRejectsInvalidIDs(t *testing.T) {
+	uuidService := &recordingService[string]{}
+	uuidRouter := newIDRouter(uuidService, ParseUUID)
+	uintService := &recordingService[uint]{}
+	uintRouter := newIDRouter(uintService, ParseUintID)
+
+	for _, id := range []string{"not-a-uuid", "123", "%27%20OR%201=1"} {
+		assertIDRejected(t, uuidRouter, id)
+	}
+	for _, id := range []string{"0", "-1", "abc", "3f2504e0-4f89-41d3-9a0c-0305e82c3301"} {
+		assertIDRejected(t, uintRouter, id)
+	}
+	if len(uuidService.ids) != 0 || len(uintService.ids) != 0 {
+		t.Fatalf("invalid ids reached the service: %v %v", uuidService.ids, uintService.ids)
+	}
+}
+
+func TestBaseControllerPassesCanonicalIDs(t *testing.T) {
+	uuidService := &recordingService[string]{}
+	uuidRouter := newIDRouter(uuidService, ParseUUID)
+	uintService := &recordingService[uint]{}
+	uintRouter := newIDRouter(uintService, ParseUintID)
+
+	for _, route := range idRoutes {
+		serveID(uuidRouter, route.method, "/items/3F2504E0-4F89-41D3-9A0C-0305E82C3301"+route.suffix)
+		serveID(uintRouter, route.method, "/items/42"+route.suffix)
+	}
+	// Update GetByID ile yükler; her uç servise bir kez ulaşır.
+	if len(uuidService.ids) != len(idRoutes) || len(uintService.ids) != len(idRoutes) {
+		t.Fatalf("service calls = %v %v, want one per route", uuidService.ids, uintService.ids)
+	}
+	for _, id := range uuidService.ids {
+		if id != "3f2504e0-4f89-41d3-9a0c-0305e82c3301" {
+			t.Errorf("service got %q, want the canonical uuid", id)
+		}
+	}
+	for _, id := range uintService.ids {
+		if id != 42 {
+			t.Errorf("service got %d, want 42", id)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	orgreq "go-initial-project/requests/org"
+	orgres "go-initial-project/responses/org"
+	"go-initial-project/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvitationController organizasyon davetleri. Yönetim uçları aktif tenant
+// üzerinde çalışır; kabul ucu davet edilen kullanıcının kendi oturumuyla çağrılır.
+type InvitationController struct {
+	tokenService        *service.TokenService
+	organizationService *service.OrganizationService
+	invitationService   *service.InvitationService
+}
+
+func NewInvitationController(
+	tokenService *service.TokenService,
+	organizationService *service.OrganizationService,
+	invitationService *service.InvitationService,
+) *InvitationController {
+	return &InvitationController{
+		tokenService:        tokenService,
+		organizationService: organizationService,
+		invitationService:   invitationService,
+	}
+}
+
+func (ic *InvitationController) RegisterRoutes(r *gin.RouterGroup) {
+	invitations := r.Group("/orgs/current/invitations",
+		middleware.AuthRequired(ic.tokenService),
+		middleware.RejectAPIKey(),
+		middleware.ResolveTenant(ic.organizationService),
+		middleware.RequireOrgPermission(entity.OrgPermMembersManage),
+	)
+	{
+		invitations.GET("", ic.List)
+		invitations.POST("", middleware.RejectImpersonation(), middleware.RequireVerifiedEmail(), ic.Create)
+		invitations.POST("/:id/resend", middleware.RejectImpersonation(), ic.Resend)
+		invitations.DELETE("/:id", middleware.RejectImpersonation(), ic.Revoke)
+	}
+
+	r.POST("/invitations/accept", middleware.AuthRequired(ic.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation(), ic.Accept)
+}
+
+// List godoc
+// @Summary List invitations of the active organization
+// @Tags invitations
+// @Security BearerAuth
+// @Produce json
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Success 200 {array} org.InvitationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /orgs/current/invitations [get]
+func (ic *InvitationController) List(ctx *gin.Context) {
+	invitations, err := ic.invitationService.List(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list invitations"})
+		return
+	}
+	now := time.Now()
+	res := make([]orgres.InvitationResponse, len(invitations))
+	for i := range invitations {
+		res[i] = newInvitationResponse(&invitations[i], now)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Create godoc
+// @Summary Invite someone to the active organization
+// @Description E-mail a single-use invitation link with a preassigned role. Only owners can invite owners.
+// @Tags invitations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Param data body org.CreateInvitationRequest true "Invitation"
+// @Success 201 {object} org.InvitationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /orgs/current/invitations [post]
+func (ic *InvitationController) Create(ctx *gin.Context) {
+	var req orgreq.CreateInvitationRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	invitation, err := ic.invitationService.Create(ctx.Request.Context(), user, ctx.GetString("org_role"), req.Email, req.Role, clientInfo(ctx))
+	if err != nil {
+		respondInvitationError(ctx, err)
+		return
+	}
+	invitation.InvitedBy = user
+	ctx.JSON(http.StatusCreated, newInvitationResponse(invitation, time.Now()))
+}
+
+// Resend godoc
+// @Summary Resend an invitation
+// @Description Issue a new link for a pending invitation and restart its expiry. The previous link stops working.
+// @Tags invitations
+// @Security BearerAuth
+// @Produce json
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} org.InvitationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orgs/current/invitations/{id}/resend [post]
+func (ic *InvitationController) Resend(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	invitation, err := ic.invitationService.Resend(ctx.Request.Context(), user, id, clientInfo(ctx))
+	if err != nil {
+		respondInvitationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newInvitationResponse(invitation, time.Now()))
+}
+
+// Revoke godoc
+// @Summary Revoke an invitation
+// @Tags invitations
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Param id path string true "Invitation ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orgs/current/invitations/{id} [delete]
+func (ic *InvitationController) Revoke(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+
+	if err := ic.invitationService.Revoke(ctx.Request.Context(), ctx.GetString("user_id"), id, clientInfo(ctx)); err != nil {
+		respondInvitationError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// Accept godoc
+// @Summary Accept an invitation
+// @Description Join the organization with the token from the invitation e-mail. The invitation must have been sent to the current user's e-mail address. New users accept by passing invitation_token to /auth/register.
+// @Tags invitations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body org.AcceptInvitationRequest true "Invitation token"
+// @Success 200 {object} org.OrganizationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /invitations/accept [post]
+func (ic *InvitationController) Accept(ctx *gin.Context) {
+	var req orgreq.AcceptInvitationRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	invitation, err := ic.invitationService.Pending(ctx.Request.Context(), req.Token)
+	if err == nil {
+		err = ic.invitationService.Accept(ctx.Request.Context(), user, invitation, clientInfo(ctx))
+	}
+	if err != nil {
+		respondInvitationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newOrganizationResponse(invitation.Organization, invitation.Role))
+}
+
+func respondInvitationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInvitation):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvitationEmailMismatch):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAlreadyInvited), errors.Is(err, service.ErrAlreadyMember), errors.Is(err, service.ErrInvitationNotPending):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		respondOrganizationError(ctx, err)
+	}
+}
+
+func newInvitationResponse(invitation *entity.Invitation, now time.Time) orgres.InvitationResponse {
+	res := orgres.InvitationResponse{
+		ID:         invitation.ID,
+		Email:      invitation.Email,
+		Role:       invitation.Role,
+		Status:     invitation.State(now),
+		ExpiresAt:  invitation.ExpiresAt,
+		SentAt:     invitation.SentAt,
+		AcceptedAt: invitation.AcceptedAt,
+		CreatedAt:  invitation.CreatedAt,
+	}
+	if invitation.InvitedBy != nil {
+		inviter := newUserResponse(invitation.InvitedBy)
+		res.InvitedBy = &inviter
+	}
+	return res
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	authreq "go-initial-project/requests/auth"
+	"go-initial-project/service"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MagicLinkController struct {
+	tokenService     *service.TokenService
+	mfaService       *service.MFAService
+	magicLinkService *service.MagicLinkService
+}
+
+func NewMagicLinkController(tokenService *service.TokenService, mfaService *service.MFAService, magicLinkService *service.MagicLinkService) *MagicLinkController {
+	return &MagicLinkController{tokenService: tokenService, mfaService: mfaService, magicLinkService: magicLinkService}
+}
+
+func (mlc *MagicLinkController) RegisterRoutes(r *gin.RouterGroup) {
+	magic := r.Group("/auth/magic-link")
+	{
+		magic.POST("", mlc.Send)
+		magic.POST("/exchange", mlc.Exchange)
+	}
+}
+
+// Send godoc
+// @Summary Request a sign-in link
+// @Description E-mail a single-use, short-lived sign-in link. Always returns 202 so that registered e-mails cannot be discovered. Limited per e-mail and IP.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body auth.MagicLinkRequest true "E-mail"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/magic-link [post]
+func (mlc *MagicLinkController) Send(ctx *gin.Context) {
+	var req authreq.MagicLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := mlc.magicLinkService.Send(req.Email, clientInfo(ctx)); err != nil {
+		if errors.Is(err, service.ErrMagicLinkThrottled) {
+			respondThrottled(ctx, err)
+			return
+		}
+		log.Println("❌ Magic link request err:", err)
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "if the e-mail is registered, a sign-in link has been sent"})
+}
+
+// Exchange godoc
+// @Summary Sign in with a magic link
+// @Description Exchange the token from the sign-in link for access and refresh tokens. If two-factor authentication is enabled, an MFA challenge is returned instead.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body auth.MagicLinkExchangeRequest true "Token from the link"
+// @Success 200 {object} auth.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/magic-link/exchange [post]
+func (mlc *MagicLinkController) Exchange(ctx *gin.Context) {
+	var req authreq.MagicLinkExchangeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := mlc.magicLinkService.Exchange(req.Token, clientInfo(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPurposeToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not complete login"})
+		return
+	}
+	respondWithLogin(ctx, mlc.tokenService, mlc.mfaService, user)
+}
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"encoding/base64"
+	"errors"
+	"go-initial-project/middleware"
+	authreq "go-initial-project/requests/auth"
+	authres "go-initial-project/responses/auth"
+	"go-initial-project/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAController struct {
+	tokenService *service.TokenService
+	mfaService   *service.MFAService
+}
+
+func NewMFAController(tokenService *service.TokenService, mfaService *service.MFAService) *MFAController {
+	return &MFAController{tokenService: tokenService, mfaService: mfaService}
+}
+
+func (mc *MFAController) RegisterRoutes(r *gin.RouterGroup) {
+	mfa := r.Group("/auth/mfa", middleware.AuthRequired(mc.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation())
+	{
+		mfa.POST("/totp/enroll", mc.EnrollTOTP)
+		mfa.POST("/totp/confirm", mc.ConfirmTOTP)
+		mfa.POST("/totp/disable", mc.DisableTOTP)
+		mfa.POST("/recovery-codes", mc.RegenerateRecoveryCodes)
+	}
+}
+
+// EnrollTOTP godoc
+// @Summary Start TOTP enrollment
+// @Description Generate a new TOTP secret. Add it to an authenticator app via the otpauth URL or QR code, then confirm with a code.
+// @Tags mfa
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} auth.MFAEnrollResponse
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/mfa/totp/enroll [post]
+func (mc *MFAController) EnrollTOTP(ctx *gin.Context) {
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	enrollment, err := mc.mfaService.EnrollTOTP(user)
+	if err != nil {
+		if errors.Is(err, service.ErrMFAAlreadyEnabled) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not start enrollment"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, authres.MFAEnrollResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURL: enrollment.URL,
+		QRCodePNG:  "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRPNG),
+	})
+}
+
+// ConfirmTOTP godoc
+// @Summary Confirm TOTP enrollment
+// @Description Verify the first code, enable two-factor authentication and return one-time recovery codes
+// @Tags mfa
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body auth.MFACodeRequest true "TOTP code"
+// @Success 200 {object} auth.RecoveryCodesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/mfa/totp/confirm [post]
+func (mc *MFAController) ConfirmTOTP(ctx *gin.Context) {
+	var req authreq.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	codes, err := mc.mfaService.ConfirmTOTP(user, req.Code, clientInfo(ctx))
+	if err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, authres.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP godoc
+// @Summary Disable TOTP
+// @Description Turn off two-factor authentication. Requires a current TOTP or recovery code.
+// @Tags mfa
+// @Security BearerAuth
+// @Accept json
+// @Param data body auth.MFACodeRequest true "TOTP or recovery code"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/mfa/totp/disable [post]
+func (mc *MFAController) DisableTOTP(ctx *gin.Context) {
+	var req authreq.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := mc.mfaService.DisableTOTP(user, req.Code, clientInfo(ctx)); err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Invalidate existing recovery codes and return a new set. Requires a current TOTP code.
+// @Tags mfa
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body auth.MFACodeRequest true "TOTP code"
+// @Success 200 {object} auth.RecoveryCodesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/mfa/recovery-codes [post]
+func (mc *MFAController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req authreq.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	codes, err := mc.mfaService.ResetRecoveryCodes(user, req.Code, clientInfo(ctx))
+	if err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, authres.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func respondMFAError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode), errors.Is(err, service.ErrMFANotEnrolled):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFAAlreadyEnabled):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.As(err, new(*service.ThrottledError)):
+		respondThrottled(ctx, err)
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "two-factor authentication failed"})
+	}
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	authreq "go-initial-project/requests/auth"
+	authres "go-initial-project/responses/auth"
+	"go-initial-project/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthClientController struct {
+	tokenService *service.TokenService
+	oauthService *service.OAuthService
+}
+
+func NewOAuthClientController(tokenService *service.TokenService, oauthService *service.OAuthService) *OAuthClientController {
+	return &OAuthClientController{tokenService: tokenService, oauthService: oauthService}
+}
+
+func (occ *OAuthClientController) RegisterRoutes(r *gin.RouterGroup) {
+	clients := r.Group("/oauth/clients",
+		middleware.AuthRequired(occ.tokenService),
+		middleware.RejectAPIKey(),
+		middleware.RejectImpersonation(),
+		middleware.RequirePermission(entity.PermOAuthClientsManage),
+	)
+	{
+		clients.GET("", occ.List)
+		clients.POST("", middleware.RequireVerifiedEmail(), occ.Create)
+		clients.DELETE("/:id", occ.Delete)
+	}
+}
+
+// List godoc
+// @Summary List OAuth clients
+// @Description List registered third-party applications. Secrets are never returned.
+// @Tags oauth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} auth.OAuthClientResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /oauth/clients [get]
+func (occ *OAuthClientController) List(ctx *gin.Context) {
+	clients, err := occ.oauthService.ListClients()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list clients"})
+		return
+	}
+
+	res := make([]authres.OAuthClientResponse, len(clients))
+	for i := range clients {
+		res[i] = newOAuthClientResponse(&clients[i])
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Create godoc
+// @Summary Register OAuth client
+// @Description Register a third-party application. Scopes are permission names the client may request. The client secret is shown only once; public clients get none and must use PKCE.
+// @Tags oauth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body auth.CreateOAuthClientRequest true "Client name, redirect URIs, grant types and scopes"
+// @Success 201 {object} auth.OAuthClientCreatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /oauth/clients [post]
+func (occ *OAuthClientController) Create(ctx *gin.Context) {
+	var req authreq.CreateOAuthClientRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	client, secret, err := occ.oauthService.CreateClient(req.Name, req.RedirectURIs, req.GrantTypes, req.Scopes, req.Public)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOAuthClient) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not create client"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, authres.OAuthClientCreatedResponse{
+		OAuthClientResponse: newOAuthClientResponse(client),
+		Secret:              secret,
+	})
+}
+
+// Delete godoc
+// @Summary Delete OAuth client
+// @Description Delete a client together with its consents; every token it was issued stops working
+// @Tags oauth
+// @Security BearerAuth
+// @Param id path string true "Client ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /oauth/clients/{id} [delete]
+func (occ *OAuthClientController) Delete(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	ok, err := occ.oauthService.DeleteClient(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete client"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "client not found"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func newOAuthClientResponse(client *entity.OAuthClient) authres.OAuthClientResponse {
+	return authres.OAuthClientResponse{
+		ID:           client.ID,
+		Name:         client.Name,
+		Public:       client.Public,
+		RedirectURIs: nonNil(client.RedirectURIs),
+		GrantTypes:   nonNil(client.GrantTypes),
+		Scopes:       nonNil(client.Scopes),
+		CreatedAt:    client.CreatedAt,
+	}
+}
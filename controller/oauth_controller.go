@@ -0,0 +1,375 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	authreq "go-initial-project/requests/auth"
+	authres "go-initial-project/responses/auth"
+	"go-initial-project/service"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthController struct {
+	tokenService *service.TokenService
+	oauthService *service.OAuthService
+}
+
+func NewOAuthController(tokenService *service.TokenService, oauthService *service.OAuthService) *OAuthController {
+	return &OAuthController{tokenService: tokenService, oauthService: oauthService}
+}
+
+func (oc *OAuthController) RegisterRoutes(r *gin.RouterGroup) {
+	oauth := r.Group("/oauth")
+	{
+		// Client'ların çağırdığı uçlar; client kimliği Basic auth veya form ile gelir.
+		oauth.POST("/token", oc.Token)
+		oauth.POST("/introspect", oc.Introspect)
+		oauth.POST("/revoke", oc.Revoke)
+	}
+
+	user := oauth.Group("", middleware.AuthRequired(oc.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation())
+	{
+		user.GET("/authorize", oc.Prepare)
+		user.POST("/authorize", oc.Authorize)
+		user.GET("/consents", oc.ListConsents)
+		user.DELETE("/consents/:client_id", oc.RevokeConsent)
+	}
+}
+
+// Prepare godoc
+// @Summary Validate an authorization request
+// @Description Validate the client's authorization request (authorization code + PKCE S256) and return what the consent screen should show. consent_required is false if the user already approved these scopes.
+// @Tags oauth
+// @Security BearerAuth
+// @Produce json
+// @Param response_type query string true "Must be code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string false "Space separated scopes"
+// @Param state query string false "Opaque value returned to the client"
+// @Param code_challenge query string true "PKCE challenge"
+// @Param code_challenge_method query string true "Must be S256"
+// @Success 200 {object} auth.AuthorizationPromptResponse
+// @Failure 400 {object} auth.OAuthErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /oauth/authorize [get]
+func (oc *OAuthController) Prepare(ctx *gin.Context) {
+	var req authreq.AuthorizeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid query"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	prompt, err := oc.oauthService.PrepareAuthorization(ctx.GetString("user_id"), authorizationRequest(req))
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, authres.AuthorizationPromptResponse{
+		Client:          authres.OAuthClientSummary{ID: prompt.Client.ID, Name: prompt.Client.Name},
+		Scopes:          nonNil(prompt.Scopes),
+		ConsentRequired: prompt.ConsentRequired,
+	})
+}
+
+// Authorize godoc
+// @Summary Approve or deny an authorization request
+// @Description Record the user's decision. Returns the client's redirect_uri with code and state, or with error=access_denied if the user denied.
+// @Tags oauth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body auth.AuthorizeDecisionRequest true "Authorization request parameters and the decision"
+// @Success 200 {object} auth.AuthorizationRedirectResponse
+// @Failure 400 {object} auth.OAuthErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /oauth/authorize [post]
+func (oc *OAuthController) Authorize(ctx *gin.Context) {
+	var req authreq.AuthorizeDecisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	redirect, err := oc.oauthService.Authorize(ctx.GetString("user_id"), authorizationRequest(req.AuthorizeRequest), req.Approve, clientInfo(ctx))
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, authres.AuthorizationRedirectResponse{RedirectURI: redirect})
+}
+
+// Token godoc
+// @Summary OAuth2 token endpoint
+// @Description Exchange an authorization code (with code_verifier) or use client credentials for an access token. Confidential clients authenticate with HTTP Basic or client_id/client_secret form fields.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code or client_credentials"
+// @Param code formData string false "Authorization code"
+// @Param redirect_uri formData string false "Redirect URI used in the authorization request"
+// @Param code_verifier formData string false "PKCE verifier"
+// @Param scope formData string false "Space separated scopes (client_credentials)"
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Success 200 {object} auth.OAuthTokenResponse
+// @Failure 400 {object} auth.OAuthErrorResponse
+// @Failure 401 {object} auth.OAuthErrorResponse
+// @Router /oauth/token [post]
+func (oc *OAuthController) Token(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	var req authreq.TokenRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		respondOAuthError(ctx, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "invalid form body"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondOAuthError(ctx, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: err.Error()})
+		return
+	}
+
+	client, ok := oc.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if !ok {
+		return
+	}
+
+	var token *service.OAuthToken
+	var err error
+	switch req.GrantType {
+	case "authorization_code":
+		token, err = oc.oauthService.ExchangeCode(client, req.Code, req.RedirectURI, req.CodeVerifier, clientInfo(ctx))
+	case "client_credentials":
+		token, err = oc.oauthService.ClientCredentials(client, req.Scope)
+	default:
+		err = &service.OAuthError{Code: service.OAuthUnsupportedGrantType, Description: "unsupported grant_type " + req.GrantType}
+	}
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, authres.OAuthTokenResponse{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   token.ExpiresIn,
+		Scope:       strings.Join(token.Scopes, " "),
+	})
+}
+
+// Introspect godoc
+// @Summary OAuth2 token introspection (RFC 7662)
+// @Description Tell whether an access token issued to the calling client is still active. Tokens of other clients are reported as inactive.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access token"
+// @Param token_type_hint formData string false "access_token"
+// @Success 200 {object} auth.IntrospectionResponse
+// @Failure 400 {object} auth.OAuthErrorResponse
+// @Failure 401 {object} auth.OAuthErrorResponse
+// @Router /oauth/introspect [post]
+func (oc *OAuthController) Introspect(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+
+	req, client, ok := oc.bindTokenLookup(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := oc.oauthService.Introspect(client, req.Token)
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	res := authres.IntrospectionResponse{Active: result.Active}
+	if result.Active {
+		res.Scope = result.Scope
+		res.ClientID = result.ClientID
+		res.Subject = result.Subject
+		res.TokenType = "Bearer"
+		res.ExpiresAt = result.ExpiresAt
+		res.IssuedAt = result.IssuedAt
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Revoke godoc
+// @Summary OAuth2 token revocation (RFC 7009)
+// @Description Revoke an access token issued to the calling client. Unknown or foreign tokens are ignored and still return 200.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Param token formData string true "Access token"
+// @Param token_type_hint formData string false "access_token"
+// @Success 200
+// @Failure 400 {object} auth.OAuthErrorResponse
+// @Failure 401 {object} auth.OAuthErrorResponse
+// @Router /oauth/revoke [post]
+func (oc *OAuthController) Revoke(ctx *gin.Context) {
+	req, client, ok := oc.bindTokenLookup(ctx)
+	if !ok {
+		return
+	}
+
+	if err := oc.oauthService.Revoke(client, req.Token); err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// ListConsents godoc
+// @Summary List authorized apps
+// @Description List the third-party apps the current user has granted access to
+// @Tags oauth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} auth.OAuthConsentResponse
+// @Failure 401 {object} map[string]string
+// @Router /oauth/consents [get]
+func (oc *OAuthController) ListConsents(ctx *gin.Context) {
+	grants, err := oc.oauthService.ListGrants(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list authorized apps"})
+		return
+	}
+
+	res := make([]authres.OAuthConsentResponse, len(grants))
+	for i, grant := range grants {
+		res[i] = authres.OAuthConsentResponse{
+			Client:    authres.OAuthClientSummary{ID: grant.Client.ID, Name: grant.Client.Name},
+			Scopes:    nonNil(grant.Consent.Scopes),
+			GrantedAt: grant.Consent.UpdatedAt,
+		}
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// RevokeConsent godoc
+// @Summary Revoke an app's access
+// @Description Remove the consent for a third-party app and revoke every token it holds for the current user
+// @Tags oauth
+// @Security BearerAuth
+// @Param client_id path string true "Client ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /oauth/consents/{client_id} [delete]
+func (oc *OAuthController) RevokeConsent(ctx *gin.Context) {
+	clientID, ok := pathID(ctx, "client_id", ParseUUID)
+	if !ok {
+		return
+	}
+	ok, err := oc.oauthService.RevokeGrant(ctx.GetString("user_id"), clientID, clientInfo(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke access"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "authorized app not found"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// bindTokenLookup introspect ve revoke gövdesini okur ve client'ı doğrular.
+// Başarısızsa cevabı yazar.
+func (oc *OAuthController) bindTokenLookup(ctx *gin.Context) (*authreq.TokenLookupRequest, *entity.OAuthClient, bool) {
+	var req authreq.TokenLookupRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		respondOAuthError(ctx, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "invalid form body"})
+		return nil, nil, false
+	}
+	if err := req.Validate(); err != nil {
+		respondOAuthError(ctx, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: err.Error()})
+		return nil, nil, false
+	}
+
+	client, ok := oc.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if !ok {
+		return nil, nil, false
+	}
+	return &req, client, true
+}
+
+// authenticateClient client kimliğini önce Basic auth header'ından, yoksa
+// form alanlarından okur (RFC 6749 2.3.1). Başarısızsa cevabı yazar.
+func (oc *OAuthController) authenticateClient(ctx *gin.Context, formID, formSecret string) (*entity.OAuthClient, bool) {
+	clientID, secret := formID, formSecret
+	if id, pass, ok := ctx.Request.BasicAuth(); ok {
+		// Basic auth'ta id ve secret form-urlencoded gönderilir
+		var errID, errSecret error
+		clientID, errID = url.QueryUnescape(id)
+		secret, errSecret = url.QueryUnescape(pass)
+		if errID != nil || errSecret != nil {
+			respondOAuthError(ctx, &service.OAuthError{Code: service.OAuthInvalidClient, Description: "malformed client credentials"})
+			return nil, false
+		}
+	}
+	if clientID == "" {
+		respondOAuthError(ctx, &service.OAuthError{Code: service.OAuthInvalidClient, Description: "client authentication required"})
+		return nil, false
+	}
+
+	client, err := oc.oauthService.AuthenticateClient(clientID, secret)
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return nil, false
+	}
+	return client, true
+}
+
+func authorizationRequest(req authreq.AuthorizeRequest) service.AuthorizationRequest {
+	return service.AuthorizationRequest{
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		ResponseType:        req.ResponseType,
+		Scope:               req.Scope,
+		State:               req.State,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}
+}
+
+// respondOAuthError OAuthError'ları RFC 6749 formatında yazar; invalid_client
+// 401, diğerleri 400 döner.
+func respondOAuthError(ctx *gin.Context, err error) {
+	var oauthErr *service.OAuthError
+	if !errors.As(err, &oauthErr) {
+		ctx.JSON(http.StatusInternalServerError, authres.OAuthErrorResponse{Error: "server_error"})
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oauthErr.Code == service.OAuthInvalidClient {
+		status = http.StatusUnauthorized
+		ctx.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+	ctx.JSON(status, authres.OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
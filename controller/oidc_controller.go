@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	authreq "go-initial-project/requests/auth"
+	authres "go-initial-project/responses/auth"
+	"go-initial-project/service"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcFlowCookie     = "oidc_flow"
+	oidcFlowCookiePath = "/api/auth/oidc"
+)
+
+type OIDCController struct {
+	tokenService *service.TokenService
+	mfaService   *service.MFAService
+	oidcService  *service.OIDCService
+}
+
+func NewOIDCController(tokenService *service.TokenService, mfaService *service.MFAService, oidcService *service.OIDCService) *OIDCController {
+	return &OIDCController{tokenService: tokenService, mfaService: mfaService, oidcService: oidcService}
+}
+
+func (oc *OIDCController) RegisterRoutes(r *gin.RouterGroup) {
+	oidc := r.Group("/auth/oidc")
+	{
+		oidc.GET("/providers", oc.Providers)
+		oidc.GET("/:provider/login", oc.Login)
+		oidc.POST("/:provider/callback", oc.Callback)
+		oidc.POST("/:provider/link", middleware.AuthRequired(oc.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation(), oc.Link)
+	}
+
+	identities := r.Group("/auth/identities", middleware.AuthRequired(oc.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation())
+	{
+		identities.GET("", oc.ListIdentities)
+		identities.DELETE("/:id", oc.Unlink)
+	}
+}
+
+// Providers godoc
+// @Summary List OpenID Connect providers
+// @Description Names of the configured external identity providers
+// @Tags oidc
+// @Produce json
+// @Success 200 {object} auth.OIDCProvidersResponse
+// @Router /auth/oidc/providers [get]
+func (oc *OIDCController) Providers(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, authres.OIDCProvidersResponse{Providers: oc.oidcService.Providers()})
+}
+
+// Login godoc
+// @Summary Start OpenID Connect login
+// @Description Redirect the browser to the identity provider (authorization code + PKCE). The provider redirects back to the configured redirect URL with code and state.
+// @Tags oidc
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /auth/oidc/{provider}/login [get]
+func (oc *OIDCController) Login(ctx *gin.Context) {
+	url, ok := oc.begin(ctx, "")
+	if !ok {
+		return
+	}
+	ctx.Redirect(http.StatusFound, url)
+}
+
+// Link godoc
+// @Summary Link an external identity
+// @Description Start an OpenID Connect flow that links the provider account to the current user instead of signing in
+// @Tags oidc
+// @Security BearerAuth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} auth.OIDCAuthorizationResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/oidc/{provider}/link [post]
+func (oc *OIDCController) Link(ctx *gin.Context) {
+	url, ok := oc.begin(ctx, ctx.GetString("user_id"))
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, authres.OIDCAuthorizationResponse{AuthorizationURL: url})
+}
+
+// Callback godoc
+// @Summary Complete OpenID Connect login
+// @Description Exchange the code and state from the provider redirect for tokens. Must be sent from the browser that started the login (flow cookie). Link flows return the linked identity instead.
+// @Tags oidc
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param data body auth.OIDCCallbackRequest true "Code and state"
+// @Success 200 {object} auth.AuthResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/oidc/{provider}/callback [post]
+func (oc *OIDCController) Callback(ctx *gin.Context) {
+	var req authreq.OIDCCallbackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Flow cookie tek kullanımlıktır.
+	sealed, _ := ctx.Cookie(oidcFlowCookie)
+	oc.setFlowCookie(ctx, "", -1)
+
+	result, err := oc.oidcService.Complete(ctx.Request.Context(), ctx.Param("provider"), req.Code, req.State, sealed, clientInfo(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownProvider):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidOIDCFlow):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrOIDCExchange), errors.Is(err, service.ErrOIDCEmailMissing):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrIdentityLinked), errors.Is(err, service.ErrOIDCEmailConflict):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Println("❌ OIDC callback err:", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not complete login"})
+		}
+		return
+	}
+
+	if result.Linked {
+		ctx.JSON(http.StatusOK, newIdentityResponse(result.Identity))
+		return
+	}
+	respondWithLogin(ctx, oc.tokenService, oc.mfaService, result.User)
+}
+
+// ListIdentities godoc
+// @Summary List linked identities
+// @Description External accounts that can be used to sign in as the current user
+// @Tags oidc
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} auth.IdentityResponse
+// @Failure 401 {object} map[string]string
+// @Router /auth/identities [get]
+func (oc *OIDCController) ListIdentities(ctx *gin.Context) {
+	identities, err := oc.oidcService.ListIdentities(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list identities"})
+		return
+	}
+
+	res := make([]authres.IdentityResponse, len(identities))
+	for i := range identities {
+		res[i] = newIdentityResponse(&identities[i])
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Unlink godoc
+// @Summary Unlink an external identity
+// @Tags oidc
+// @Security BearerAuth
+// @Param id path string true "Identity ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/identities/{id} [delete]
+func (oc *OIDCController) Unlink(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	ok, err := oc.oidcService.Unlink(ctx.GetString("user_id"), id, clientInfo(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not unlink identity"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "identity not found"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// begin flow'u başlatıp cookie'yi yazar; hata olursa cevabı kendisi yazar.
+func (oc *OIDCController) begin(ctx *gin.Context, linkUserID string) (string, bool) {
+	url, sealed, err := oc.oidcService.Begin(ctx.Request.Context(), ctx.Param("provider"), linkUserID)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownProvider) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return "", false
+		}
+		log.Println("❌ OIDC begin err:", err)
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "identity provider unavailable"})
+		return "", false
+	}
+	oc.setFlowCookie(ctx, sealed, int(config.AppConfig.OIDC.FlowTTL.Seconds()))
+	return url, true
+}
+
+func (oc *OIDCController) setFlowCookie(ctx *gin.Context, value string, maxAge int) {
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcFlowCookie, value, maxAge, oidcFlowCookiePath, "", config.AppConfig.App.Env == "production", true)
+}
+
+func newIdentityResponse(identity *entity.Identity) authres.IdentityResponse {
+	return authres.IdentityResponse{
+		ID:        identity.ID,
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
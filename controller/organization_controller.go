@@ -0,0 +1,348 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	orgreq "go-initial-project/requests/org"
+	orgres "go-initial-project/responses/org"
+	"go-initial-project/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OrganizationController organizasyonlar ve üyelikleri. /orgs/current altındaki
+// uçlar aktif tenant üzerinde çalışır (bkz. middleware.ResolveTenant).
+type OrganizationController struct {
+	tokenService        *service.TokenService
+	organizationService *service.OrganizationService
+}
+
+func NewOrganizationController(tokenService *service.TokenService, organizationService *service.OrganizationService) *OrganizationController {
+	return &OrganizationController{tokenService: tokenService, organizationService: organizationService}
+}
+
+func (oc *OrganizationController) RegisterRoutes(r *gin.RouterGroup) {
+	orgs := r.Group("/orgs", middleware.AuthRequired(oc.tokenService))
+	{
+		orgs.GET("", oc.List)
+		orgs.POST("", middleware.RejectAPIKey(), middleware.RejectImpersonation(), middleware.RequireVerifiedEmail(), oc.Create)
+		orgs.POST("/:id/switch", middleware.RejectAPIKey(), oc.Switch)
+
+		current := orgs.Group("/current", middleware.ResolveTenant(oc.organizationService))
+		{
+			current.GET("", oc.Current)
+			current.PATCH("", middleware.RejectAPIKey(), middleware.RequireOrgPermission(entity.OrgPermUpdate), oc.Update)
+			current.DELETE("", middleware.RejectAPIKey(), middleware.RejectImpersonation(), middleware.RequireOrgPermission(entity.OrgPermDelete), oc.Delete)
+			current.GET("/members", middleware.RequireOrgPermission(entity.OrgPermMembersRead), oc.Members)
+			current.PUT("/members/:user_id", middleware.RejectAPIKey(), middleware.RequireOrgPermission(entity.OrgPermMembersManage), oc.ChangeRole)
+			current.DELETE("/members/:user_id", middleware.RejectAPIKey(), middleware.RequireOrgPermission(entity.OrgPermMembersManage), oc.RemoveMember)
+			current.POST("/leave", middleware.RejectAPIKey(), middleware.RejectImpersonation(), oc.Leave)
+		}
+	}
+}
+
+// List godoc
+// @Summary List my organizations
+// @Tags organizations
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} org.OrganizationResponse
+// @Failure 401 {object} map[string]string
+// @Router /orgs [get]
+func (oc *OrganizationController) List(ctx *gin.Context) {
+	orgs, err := oc.organizationService.ListForUser(ctx.Request.Context(), ctx.GetString("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list organizations"})
+		return
+	}
+	res := make([]orgres.OrganizationResponse, len(orgs))
+	for i := range orgs {
+		res[i] = newUserOrganizationResponse(&orgs[i])
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Create godoc
+// @Summary Create an organization
+// @Description Create an organization with the current user as its owner. The slug is derived from the name when omitted.
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body org.CreateOrganizationRequest true "Organization"
+// @Success 201 {object} org.OrganizationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /orgs [post]
+func (oc *OrganizationController) Create(ctx *gin.Context) {
+	var req orgreq.CreateOrganizationRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	org, err := oc.organizationService.Create(ctx.Request.Context(), user, req.Name, req.Slug, clientInfo(ctx))
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, newOrganizationResponse(org, entity.OrgRoleOwner))
+}
+
+// Switch godoc
+// @Summary Switch the active organization
+// @Description Make the organization active for the current session and return an access token with an "org" claim. Later refreshes keep the organization.
+// @Tags organizations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {object} org.SwitchOrganizationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /orgs/{id}/switch [post]
+func (oc *OrganizationController) Switch(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	token, expiresIn, err := oc.organizationService.Switch(ctx.Request.Context(), user, ctx.GetString("session_id"), id)
+	if err != nil {
+		if errors.Is(err, service.ErrSessionRequired) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "this token has no login session, send the " + middleware.TenantHeader + " header instead"})
+			return
+		}
+		respondOrganizationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, orgres.SwitchOrganizationResponse{Token: token, ExpiresIn: expiresIn, OrganizationID: id})
+}
+
+// Current godoc
+// @Summary Get the active organization
+// @Tags organizations
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Success 200 {object} org.OrganizationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /orgs/current [get]
+func (oc *OrganizationController) Current(ctx *gin.Context) {
+	org, err := oc.organizationService.Current(ctx.Request.Context())
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newOrganizationResponse(org, ctx.GetString("org_role")))
+}
+
+// Update godoc
+// @Summary Rename the active organization
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Param data body org.UpdateOrganizationRequest true "Organization"
+// @Success 200 {object} org.OrganizationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /orgs/current [patch]
+func (oc *OrganizationController) Update(ctx *gin.Context) {
+	var req orgreq.UpdateOrganizationRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
+
+	org, err := oc.organizationService.Rename(ctx.Request.Context(), ctx.GetString("user_id"), req.Name, clientInfo(ctx))
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newOrganizationResponse(org, ctx.GetString("org_role")))
+}
+
+// Delete godoc
+// @Summary Delete the active organization
+// @Description Delete the organization and all of its memberships. Owners only.
+// @Tags organizations
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /orgs/current [delete]
+func (oc *OrganizationController) Delete(ctx *gin.Context) {
+	if err := oc.organizationService.Delete(ctx.Request.Context(), ctx.GetString("user_id"), clientInfo(ctx)); err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// Members godoc
+// @Summary List members of the active organization
+// @Tags organizations
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Success 200 {array} org.MemberResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /orgs/current/members [get]
+func (oc *OrganizationController) Members(ctx *gin.Context) {
+	memberships, err := oc.organizationService.Members(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list members"})
+		return
+	}
+	res := make([]orgres.MemberResponse, 0, len(memberships))
+	for i := range memberships {
+		// Silinmiş kullanıcıların üyelikleri listelenmez.
+		if memberships[i].User == nil {
+			continue
+		}
+		res = append(res, orgres.MemberResponse{
+			User:     newUserResponse(memberships[i].User),
+			Role:     memberships[i].Role,
+			JoinedAt: memberships[i].CreatedAt,
+		})
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ChangeRole godoc
+// @Summary Change a member's role
+// @Description Only owners can grant or take away the owner role; the last owner cannot be demoted.
+// @Tags organizations
+// @Security BearerAuth
+// @Accept json
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Param user_id path string true "User ID"
+// @Param data body org.UpdateMemberRoleRequest true "Role"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /orgs/current/members/{user_id} [put]
+func (oc *OrganizationController) ChangeRole(ctx *gin.Context) {
+	userID, ok := pathID(ctx, "user_id", ParseUUID)
+	if !ok {
+		return
+	}
+	var req orgreq.UpdateMemberRoleRequest
+	if !bindRequest(ctx, &req) {
+		return
+	}
+
+	err := oc.organizationService.ChangeRole(ctx.Request.Context(), ctx.GetString("user_id"), ctx.GetString("org_role"), userID, req.Role, clientInfo(ctx))
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveMember godoc
+// @Summary Remove a member
+// @Tags organizations
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Param user_id path string true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orgs/current/members/{user_id} [delete]
+func (oc *OrganizationController) RemoveMember(ctx *gin.Context) {
+	userID, ok := pathID(ctx, "user_id", ParseUUID)
+	if !ok {
+		return
+	}
+
+	err := oc.organizationService.RemoveMember(ctx.Request.Context(), ctx.GetString("user_id"), ctx.GetString("org_role"), userID, clientInfo(ctx))
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// Leave godoc
+// @Summary Leave the active organization
+// @Tags organizations
+// @Security BearerAuth
+// @Param X-Organization-ID header string false "Organization ID, when the token has no org claim"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /orgs/current/leave [post]
+func (oc *OrganizationController) Leave(ctx *gin.Context) {
+	userID := ctx.GetString("user_id")
+	err := oc.organizationService.RemoveMember(ctx.Request.Context(), userID, ctx.GetString("org_role"), userID, clientInfo(ctx))
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func respondOrganizationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidOrgSlug):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOrgSlugTaken), errors.Is(err, service.ErrLastOwner):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOrgOwnerRequired):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotMember):
+		// Üye uçlarında hedef kullanıcı bulunamadı; Switch'te ise istek yapan üye değil.
+		if ctx.Param("user_id") == "" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "organization request failed"})
+	}
+}
+
+func newOrganizationResponse(org *entity.Organization, role string) orgres.OrganizationResponse {
+	return orgres.OrganizationResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		Slug:      org.Slug,
+		Role:      role,
+		CreatedAt: org.CreatedAt,
+	}
+}
+
+func newUserOrganizationResponse(org *entity.UserOrganization) orgres.OrganizationResponse {
+	return newOrganizationResponse(&org.Organization, org.Role)
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	"go-initial-project/service"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestOrganizationRoutesRequireUserSession(t *testing.T) {
+	env := newTestEnv(t)
+	r := env.router(NewOrganizationController(env.tokens, env.organizations))
+
+	owner := env.createUser(t, "owner@example.com")
+	member := env.createUser(t, "member@example.com")
+	admin := env.createUser(t, "support@example.com")
+	org, err := env.organizations.Create(context.Background(), owner, "Acme", "", service.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.addMember(t, org.ID, member.ID, entity.OrgRoleMember)
+
+	_, apiKey, err := env.apiKeys.Create(member.ID, "ci", nil, nil, service.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	impersonation, _, err := env.tokens.IssueImpersonationToken(member, admin.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := env.tokens.IssueTokens(member, service.ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viaAPIKey := map[string]string{"X-API-Key": apiKey, middleware.TenantHeader: org.ID}
+	viaImpersonation := map[string]string{"Authorization": "Bearer " + impersonation, middleware.TenantHeader: org.ID}
+	viaSession := map[string]string{"Authorization": "Bearer " + session.AccessToken, middleware.TenantHeader: org.ID}
+
+	tests := []struct {
+		method, path      string
+		rejectImpersonate bool
+	}{
+		{http.MethodPost, "/api/orgs", true},
+		{http.MethodPost, "/api/orgs/" + org.ID + "/switch", false},
+		{http.MethodPatch, "/api/orgs/current", false},
+		{http.MethodDelete, "/api/orgs/current", true},
+		{http.MethodPut, "/api/orgs/current/members/" + owner.ID, false},
+		{http.MethodDelete, "/api/orgs/current/members/" + owner.ID, false},
+		{http.MethodPost, "/api/orgs/current/leave", true},
+	}
+	for _, tt := range tests {
+		w := serve(r, tt.method, tt.path, viaAPIKey)
+		if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "requires a user session") {
+			t.Errorf("api key %s %s: %d %s, want 403", tt.method, tt.path, w.Code, w.Body.String())
+		}
+		if !tt.rejectImpersonate {
+			continue
+		}
+		w = serve(r, tt.method, tt.path, viaImpersonation)
+		if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "impersonating") {
+			t.Errorf("impersonation %s %s: %d %s, want 403", tt.method, tt.path, w.Code, w.Body.String())
+		}
+	}
+
+	// API anahtarı okuyabilir; üye hâlâ organizasyondadır.
+	if w := serve(r, http.MethodGet, "/api/orgs/current/members", viaAPIKey); w.Code != http.StatusOK {
+		t.Fatalf("api key GET members: %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(r, http.MethodPost, "/api/orgs/current/leave", viaSession); w.Code != http.StatusNoContent {
+		t.Fatalf("session leave: %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(r, http.MethodGet, "/api/orgs/current", viaSession); w.Code != http.StatusForbidden {
+		t.Fatalf("after leave: %d %s, want 403", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	"go-initial-project/principal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CurrentPrincipal AuthRequired veya APIKeyAuth'un doğruladığı principal'ı döner.
+// Route korumasızsa false döner.
+func CurrentPrincipal(ctx *gin.Context) (*principal.Principal, bool) {
+	value, _ := ctx.Get(middleware.PrincipalKey)
+	p, ok := value.(*principal.Principal)
+	return p, ok && p != nil
+}
+
+// MustUser isteği yapan kullanıcıyı döner; kullanıcı istek başına bir kez
+// yüklenir. Kullanıcı yoksa veya yüklenemezse cevabı yazar ve false döner.
+func MustUser(ctx *gin.Context) (*entity.User, bool) {
+	p, ok := CurrentPrincipal(ctx)
+	if !ok || p.UserID == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
+	}
+
+	user, err := p.User(ctx.Request.Context())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not load user"})
+		}
+		return nil, false
+	}
+	return user, true
+}
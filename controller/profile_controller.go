@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/middleware"
+	authreq "go-initial-project/requests/auth"
+	userreq "go-initial-project/requests/user"
+	"go-initial-project/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileController giriş yapmış kullanıcının kendi profilini düzenlemesi için.
+type ProfileController struct {
+	userService              *service.UserService
+	tokenService             *service.TokenService
+	emailVerificationService *service.EmailVerificationService
+	phoneVerificationService *service.PhoneVerificationService
+}
+
+func NewProfileController(
+	userService *service.UserService,
+	tokenService *service.TokenService,
+	emailVerificationService *service.EmailVerificationService,
+	phoneVerificationService *service.PhoneVerificationService,
+) *ProfileController {
+	return &ProfileController{
+		userService:              userService,
+		tokenService:             tokenService,
+		emailVerificationService: emailVerificationService,
+		phoneVerificationService: phoneVerificationService,
+	}
+}
+
+func (pc *ProfileController) RegisterRoutes(r *gin.RouterGroup) {
+	me := r.Group("/auth/me", middleware.AuthRequired(pc.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation())
+	{
+		me.PATCH("", pc.Update)
+		me.POST("/phone/send-code", pc.SendPhoneCode)
+		me.POST("/phone/verify", pc.VerifyPhone)
+	}
+}
+
+// Update godoc
+// @Summary Update current user
+// @Description Update the current user's name, e-mail or phone (E.164). Omitted fields are left unchanged. Changing the e-mail or phone resets its verification; a new verification link is sent to the new e-mail.
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body user.UpdateUserRequest true "Profile fields"
+// @Success 200 {object} user.UserResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/me [patch]
+func (pc *ProfileController) Update(ctx *gin.Context) {
+	var req userreq.UpdateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	emailChanged, _, err := pc.userService.UpdateProfile(user, service.ProfileUpdate{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Email:     req.Email,
+		Phone:     req.Phone,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrEmailTaken) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not update profile"})
+		return
+	}
+
+	if emailChanged {
+		if err := pc.emailVerificationService.Send(user); err != nil {
+			log.Println("❌ Verification mail err:", err)
+		}
+	}
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// SendPhoneCode godoc
+// @Summary Send phone verification code
+// @Description Send a 6-digit code by SMS to the current user's phone number
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 202 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/me/phone/send-code [post]
+func (pc *ProfileController) SendPhoneCode(ctx *gin.Context) {
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := pc.phoneVerificationService.Send(user, clientInfo(ctx)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoPhone):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPhoneAlreadyVerified):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPhoneVerificationThrottled):
+			interval := config.AppConfig.Auth.PhoneVerificationResendInterval
+			ctx.Header("Retry-After", strconv.Itoa(int(interval.Seconds())))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not send verification code"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "verification code sent"})
+}
+
+// VerifyPhone godoc
+// @Summary Verify phone number
+// @Description Confirm the current user's phone number with the code sent by SMS
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param data body auth.VerifyPhoneRequest true "Verification code"
+// @Success 200 {object} user.UserResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /auth/me/phone/verify [post]
+func (pc *ProfileController) VerifyPhone(ctx *gin.Context) {
+	var req authreq.VerifyPhoneRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := MustUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := pc.phoneVerificationService.Verify(user, req.Code, clientInfo(ctx)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPhoneCode), errors.Is(err, service.ErrNoPhone):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPhoneAlreadyVerified):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not verify phone"})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	authres "go-initial-project/responses/auth"
+	"go-initial-project/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	tokenService   *service.TokenService
+	sessionService *service.SessionService
+}
+
+func NewSessionController(tokenService *service.TokenService, sessionService *service.SessionService) *SessionController {
+	return &SessionController{tokenService: tokenService, sessionService: sessionService}
+}
+
+func (sc *SessionController) RegisterRoutes(r *gin.RouterGroup) {
+	sessions := r.Group("/auth/sessions", middleware.AuthRequired(sc.tokenService), middleware.RejectAPIKey(), middleware.RejectImpersonation())
+	{
+		sessions.GET("", sc.List)
+		sessions.DELETE("", sc.RevokeOthers)
+		sessions.DELETE("/:id", sc.Revoke)
+	}
+}
+
+// List godoc
+// @Summary List sessions
+// @Description Devices where the current user is logged in
+// @Tags sessions
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} auth.SessionResponse
+// @Failure 401 {object} map[string]string
+// @Router /auth/sessions [get]
+func (sc *SessionController) List(ctx *gin.Context) {
+	sessions, err := sc.sessionService.List(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not list sessions"})
+		return
+	}
+
+	current := ctx.GetString("session_id")
+	res := make([]authres.SessionResponse, len(sessions))
+	for i := range sessions {
+		res[i] = newSessionResponse(&sessions[i], current)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Revoke godoc
+// @Summary Revoke a session
+// @Description Log out a single device. Its refresh tokens stop working and its access tokens are rejected.
+// @Tags sessions
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/sessions/{id} [delete]
+func (sc *SessionController) Revoke(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	ok, err := sc.sessionService.Revoke(ctx.GetString("user_id"), id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke session"})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RevokeOthers godoc
+// @Summary Log out everywhere else
+// @Description Revoke every session of the current user except the one making the request
+// @Tags sessions
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} auth.RevokedSessionsResponse
+// @Failure 401 {object} map[string]string
+// @Router /auth/sessions [delete]
+func (sc *SessionController) RevokeOthers(ctx *gin.Context) {
+	count, err := sc.sessionService.RevokeOthers(ctx.GetString("user_id"), ctx.GetString("session_id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke sessions"})
+		return
+	}
+	ctx.JSON(http.StatusOK, authres.RevokedSessionsResponse{Revoked: count})
+}
+
+func newSessionResponse(session *entity.Session, currentID string) authres.SessionResponse {
+	return authres.SessionResponse{
+		ID:         session.ID,
+		Device:     session.Device,
+		IP:         session.IP,
+		UserAgent:  session.UserAgent,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		Current:    session.ID == currentID,
+	}
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	"go-initial-project/repository"
+	"go-initial-project/service"
+	"go-initial-project/tenant"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+func TestMain(m *testing.M) {
+	config.LoadEnv()
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// testEnv controller testleri için gerçek servisler ve SQLite veritabanı.
+type testEnv struct {
+	db            *gorm.DB
+	users         *service.UserService
+	tokens        *service.TokenService
+	apiKeys       *service.APIKeyService
+	organizations *service.OrganizationService
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SQLite satır kilidi desteklemez; FOR UPDATE yok sayılır.
+	db.ClauseBuilders["FOR"] = func(clause.Clause, clause.Builder) {}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(
+		&entity.User{},
+		&entity.Activity{},
+		&entity.RefreshToken{},
+		&entity.RevokedToken{},
+		&entity.Permission{},
+		&entity.Role{},
+		&entity.Session{},
+		&entity.APIKey{},
+		&entity.Organization{},
+		&entity.Membership{},
+		&entity.Invitation{},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := config.NewKeyring()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roleRepo := repository.NewRoleRepository(db)
+	refreshTokens := repository.NewRefreshTokenRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	revocations := service.NewDBRevocationStore(repository.NewRevokedTokenRepository(db), time.Hour)
+	roles := service.NewRoleService(roleRepo)
+	activities := service.NewActivityService(repository.NewActivityRepository(db))
+	sessions := service.NewSessionService(repository.NewSessionRepository(db), refreshTokens, revocations)
+
+	env := &testEnv{db: db}
+	env.users = service.NewUserService(repository.NewUserRepository(db), roleRepo, nil, nil)
+	env.tokens = service.NewTokenService(keys, env.users, roles, refreshTokens, apiKeyRepo, revocations, sessions)
+	env.apiKeys = service.NewAPIKeyService(apiKeyRepo, env.users, roles, activities)
+	env.organizations = service.NewOrganizationService(repository.NewOrganizationRepository(db), repository.NewMembershipRepository(db), env.tokens, activities)
+	if err := roles.SeedDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+// router controller'ların route'larını main'deki gibi /api altında, API
+// anahtarı doğrulamasıyla kurar.
+func (env *testEnv) router(controllers ...interface{ RegisterRoutes(*gin.RouterGroup) }) *gin.Engine {
+	r := gin.New()
+	api := r.Group("/api", middleware.APIKeyAuth(env.apiKeys))
+	for _, c := range controllers {
+		c.RegisterRoutes(api)
+	}
+	return r
+}
+
+func (env *testEnv) createUser(t *testing.T, email string) *entity.User {
+	t.Helper()
+	now := time.Now()
+	user, err := env.users.Create(entity.User{FirstName: "Test", LastName: "User", Email: email, EmailVerifiedAt: &now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+// addMember kullanıcıyı organizasyona role ile ekler.
+func (env *testEnv) addMember(t *testing.T, organizationID, userID, role string) {
+	t.Helper()
+	ctx := tenant.NewContext(context.Background(), organizationID)
+	if err := env.db.WithContext(ctx).Create(&entity.Membership{UserID: userID, Role: role}).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve isteği headers ile atar.
+func serve(r *gin.Engine, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
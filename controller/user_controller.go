@@ -2,39 +2,67 @@ package controller
 
 import (
 	"go-initial-project/entity"
+	"go-initial-project/middleware"
+	userreq "go-initial-project/requests/user"
+	userres "go-initial-project/responses/user"
 	"go-initial-project/service"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserController struct {
-	*BaseController[entity.User]
-	userService *service.UserService
+	*BaseController[entity.User, string, userreq.CreateUserRequest, userreq.UpdateUserRequest, userres.UserResponse]
+	userService  *service.UserService
+	roleService  *service.RoleService
+	tokenService *service.TokenService
 }
 
-func NewUserController(userService *service.UserService) *UserController {
+func NewUserController(userService *service.UserService, roleService *service.RoleService, tokenService *service.TokenService) *UserController {
 	return &UserController{
-		BaseController: NewBaseController[entity.User](userService),
+		BaseController: NewBaseController(userService, ParseUUID, userMapper),
 		userService:    userService,
+		roleService:    roleService,
+		tokenService:   tokenService,
 	}
 }
 
+// userMapper admin'in kullanıcı CRUD'u için; şifre, doğrulama tarihleri ve
+// roller bu uçlardan değiştirilemez.
+var userMapper = Mapper[entity.User, userreq.CreateUserRequest, userreq.UpdateUserRequest, userres.UserResponse]{
+	NewEntity: func(req *userreq.CreateUserRequest) entity.User {
+		return entity.User{
+			FirstName: req.FirstName,
+			LastName:  req.LastName,
+			Email:     req.Email,
+			Phone:     req.Phone,
+		}
+	},
+	ApplyUpdate: func(user *entity.User, req *userreq.UpdateUserRequest) map[string]interface{} {
+		return user.ApplyProfile(req.FirstName, req.LastName, req.Email, req.Phone)
+	},
+	ToResponse: newUserResponse,
+}
+
 func (uc *UserController) RegisterRoutes(r *gin.RouterGroup) {
-	users := r.Group("/users")
+	users := r.Group("/users", middleware.AuthRequired(uc.tokenService))
 	{
-		users.GET("", uc.GetAll)
-		users.GET("/:id", uc.GetByID)
-		users.POST("", uc.Create)
-		users.PUT("/:id", uc.Update)
-		users.DELETE("/:id", uc.Delete)
+		users.GET("", middleware.RequirePermission(entity.PermUsersRead), uc.GetAll)
+		users.GET("/:id", middleware.RequirePermission(entity.PermUsersRead), uc.GetByID)
+		users.POST("", middleware.RequirePermission(entity.PermUsersCreate), uc.Create)
+		users.PUT("/:id", middleware.RequirePermission(entity.PermUsersUpdate), uc.Update)
+		users.DELETE("/:id", middleware.RequirePermission(entity.PermUsersDelete), uc.Delete)
+		users.PUT("/:id/roles", middleware.RequirePermission(entity.PermRolesAssign), uc.AssignRoles)
 	}
 }
 
 // GetUsers godoc
 // @Summary Get all users
 // @Tags users
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
-// @Success 200 {array} entity.User
+// @Success 200 {array} user.UserResponse
 // @Router /users [get]
 func (uc *UserController) GetUsers(ctx *gin.Context) {
 	uc.BaseController.GetAll(ctx)
@@ -43,9 +71,13 @@ func (uc *UserController) GetUsers(ctx *gin.Context) {
 // GetUserByID godoc
 // @Summary Get user by ID
 // @Tags users
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
-// @Param id path int true "ID"
-// @Success 200 {object} entity.User
+// @Param id path string true "User ID"
+// @Success 200 {object} user.UserResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Router /users/{id} [get]
 func (uc *UserController) GetUserByID(ctx *gin.Context) {
 	uc.BaseController.GetByID(ctx)
@@ -54,10 +86,15 @@ func (uc *UserController) GetUserByID(ctx *gin.Context) {
 // CreateUser godoc
 // @Summary Create user
 // @Tags users
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
-// @Param data body entity.User true "User"
-// @Success 201 {object} entity.User
+// @Param data body userrequests.CreateUserRequest true "User"
+// @Success 201 {object} user.UserResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Router /users [post]
 func (uc *UserController) CreateUser(ctx *gin.Context) {
 	uc.BaseController.Create(ctx)
@@ -66,21 +103,91 @@ func (uc *UserController) CreateUser(ctx *gin.Context) {
 // UpdateUser godoc
 // @Summary Update user
 // @Tags users
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
-// @Param data body entity.User true "User"
-// @Success 200 {object} entity.User
-// @Router /users [put]
+// @Param id path string true "User ID"
+// @Param data body userrequests.UpdateUserRequest true "Fields to change"
+// @Success 200 {object} user.UserResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /users/{id} [put]
 func (uc *UserController) UpdateUser(ctx *gin.Context) {
 	uc.BaseController.Update(ctx)
 }
 
 // DeleteUser godoc
 // @Summary Delete user
+// @Description Soft-delete a user and revoke their sessions, tokens and API keys
 // @Tags users
-// @Param id path int true "ID"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
 // @Success 204
+// @Failure 400 {object} map[string]string
 // @Router /users/{id} [delete]
 func (uc *UserController) DeleteUser(ctx *gin.Context) {
-	uc.BaseController.Delete(ctx)
+	uc.Delete(ctx)
+}
+
+// Delete kullanıcıyı siler ve elindeki token'ları iptal eder; aksi halde
+// access token'lar süreleri dolana kadar kullanılabilirdi.
+func (uc *UserController) Delete(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	if err := uc.userService.Delete(id, entity.User{}); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := uc.tokenService.RevokeUserTokens(id); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke user tokens"})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// AssignRoles godoc
+// @Summary Replace user roles
+// @Tags users
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Param id path string true "User ID"
+// @Param data body userrequests.AssignRolesRequest true "Roles"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /users/{id}/roles [put]
+func (uc *UserController) AssignRoles(ctx *gin.Context) {
+	id, ok := pathID(ctx, "id", ParseUUID)
+	if !ok {
+		return
+	}
+	var req userreq.AssignRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := uc.userService.GetByID(id)
+	if err != nil {
+		respondLookupError(ctx, err)
+		return
+	}
+	if err := uc.roleService.ReplaceRoles(user.ID, req.Roles); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
 }
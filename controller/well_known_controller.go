@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"go-initial-project/keyring"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WellKnownController /.well-known altındaki herkese açık metadata uçları.
+// /api grubunun dışında, kök router'a bağlanır.
+type WellKnownController struct {
+	keys *keyring.Keyring
+}
+
+func NewWellKnownController(keys *keyring.Keyring) *WellKnownController {
+	return &WellKnownController{keys: keys}
+}
+
+func (wc *WellKnownController) RegisterRoutes(r *gin.RouterGroup) {
+	wellKnown := r.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", wc.JWKS)
+	}
+}
+
+// JWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys used to verify access tokens. HMAC secrets are never published.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} keyring.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (wc *WellKnownController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, wc.keys.JWKS())
+}
@@ -0,0 +1,57 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrCiphertext = errors.New("invalid ciphertext")
+
+// Cipher veritabanında şifreli saklanması gereken değerler (TOTP secret vb.)
+// için AES-256-GCM. Çıktı base64(nonce || ciphertext) formatındadır.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New verilen anahtar materyalinden SHA-256 ile 32 byte'lık AES anahtarı türetir.
+func New(key []byte) (*Cipher, error) {
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrCiphertext
+	}
+	size := c.aead.NonceSize()
+	if len(data) < size {
+		return "", ErrCiphertext
+	}
+	plaintext, err := c.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return "", ErrCiphertext
+	}
+	return string(plaintext), nil
+}
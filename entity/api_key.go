@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey makineler arası erişim için kullanıcıya ait uzun ömürlü anahtar.
+// Anahtar "<prefix>.<secret>" formatındadır; prefix listelerde gösterilir,
+// secret'ın sadece SHA-256 hash'i saklanır.
+type APIKey struct {
+	ID         string   `gorm:"type:uuid;primaryKey"`
+	UserID     string   `gorm:"type:uuid;index;not null"`
+	Name       string   `gorm:"size:100;not null"`
+	Prefix     string   `gorm:"size:32;uniqueIndex;not null"`
+	SecretHash string   `gorm:"size:64;not null"`
+	Scopes     []string `gorm:"serializer:json;type:text"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if k.ID == "" {
+		k.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Identity harici bir OIDC sağlayıcısındaki hesabı kullanıcıya bağlar.
+// Bir kullanıcının birden fazla identity'si olabilir; (Provider, Subject) tekildir.
+type Identity struct {
+	ID        string `gorm:"type:uuid;primaryKey"`
+	UserID    string `gorm:"type:uuid;index;not null"`
+	Provider  string `gorm:"size:50;not null;uniqueIndex:idx_identity_provider_subject"`
+	Subject   string `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject"`
+	Email     string `gorm:"size:255"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (i *Identity) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
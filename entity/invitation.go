@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Davet durumları. InvitationExpired saklanmaz; süresi geçmiş bekleyen
+// davetler için State tarafından türetilir.
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation bir e-posta adresinin organizasyona önceden belirlenmiş rolle
+// davet edilmesi. Ham token sadece e-postada bulunur, veritabanında SHA-256
+// hash'i saklanır. Bir adrese aynı anda tek bekleyen davet olabilir.
+type Invitation struct {
+	ID             string        `gorm:"type:uuid;primaryKey"`
+	OrganizationID string        `gorm:"type:uuid;not null;uniqueIndex:idx_invitation_pending,where:status = 'pending'"`
+	Email          string        `gorm:"size:255;not null;uniqueIndex:idx_invitation_pending,where:status = 'pending'"`
+	Role           string        `gorm:"size:20;not null"`
+	TokenHash      string        `gorm:"size:64;uniqueIndex;not null"`
+	Status         string        `gorm:"size:20;not null;index"`
+	InvitedByID    string        `gorm:"type:uuid;not null"`
+	InvitedBy      *User         `gorm:"foreignKey:InvitedByID"`
+	Organization   *Organization `gorm:"foreignKey:OrganizationID"`
+	AcceptedByID   *string       `gorm:"type:uuid"`
+	AcceptedAt     *time.Time
+	ExpiresAt      time.Time `gorm:"not null"`
+	SentAt         time.Time `gorm:"not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TenantScoped davetler organizasyona aittir; sorgular aktif tenant'la sınırlanır.
+func (Invitation) TenantScoped() {}
+
+func (i *Invitation) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	if i.Status == "" {
+		i.Status = InvitationPending
+	}
+	return nil
+}
+
+// State daveti süresi geçmişse InvitationExpired, değilse kayıtlı durumunu döner.
+func (i *Invitation) State(now time.Time) string {
+	if i.Status == InvitationPending && !now.Before(i.ExpiresAt) {
+		return InvitationExpired
+	}
+	return i.Status
+}
+
+// SentTo davetin email adresine gönderilip gönderilmediğini söyler; büyük/küçük harf duyarsız.
+func (i *Invitation) SentTo(email string) bool {
+	return strings.EqualFold(i.Email, email)
+}
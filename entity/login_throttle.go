@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// LoginThrottle başarısız giriş denemelerini anahtar bazında sayar.
+// Key "email:<adres>", "ip:<adres>" veya "mfa:<user id>" formatındadır.
+type LoginThrottle struct {
+	Key          string `gorm:"primaryKey;size:320"`
+	Failures     int    `gorm:"not null;default:0"`
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
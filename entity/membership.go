@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Organizasyon içi roller. Platform rollerinden (RoleAdmin, RoleUser) bağımsızdır.
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// Organizasyon izinleri "org:<kaynak>:<aksiyon>" formatındadır.
+const (
+	OrgPermUpdate        = "org:update"
+	OrgPermDelete        = "org:delete"
+	OrgPermMembersRead   = "org:members:read"
+	OrgPermMembersManage = "org:members:manage"
+)
+
+// OrgRolePermissions her organizasyon rolünün izinleri.
+var OrgRolePermissions = map[string][]string{
+	OrgRoleOwner:  {OrgPermUpdate, OrgPermDelete, OrgPermMembersRead, OrgPermMembersManage},
+	OrgRoleAdmin:  {OrgPermUpdate, OrgPermMembersRead, OrgPermMembersManage},
+	OrgRoleMember: {OrgPermMembersRead},
+}
+
+// OrgPermissionsFor role'ün izinlerini döner; bilinmeyen rolün izni yoktur.
+func OrgPermissionsFor(role string) []string {
+	return slices.Clone(OrgRolePermissions[role])
+}
+
+// Membership kullanıcının bir organizasyondaki üyeliği ve rolü.
+type Membership struct {
+	ID             string `gorm:"type:uuid;primaryKey"`
+	OrganizationID string `gorm:"type:uuid;not null;uniqueIndex:idx_membership_org_user"`
+	UserID         string `gorm:"type:uuid;not null;uniqueIndex:idx_membership_org_user;index"`
+	Role           string `gorm:"size:20;not null"`
+	User           *User  `gorm:"foreignKey:UserID"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// TenantScoped üyelikler organizasyona aittir; sorgular aktif tenant'la sınırlanır.
+func (Membership) TenantScoped() {}
+
+func (m *Membership) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OAuthAuthorizationCode kullanıcının onayından sonra client'a verilen kısa
+// ömürlü, tek kullanımlık kod. Sadece SHA-256 hash'i saklanır.
+type OAuthAuthorizationCode struct {
+	ID            string   `gorm:"type:uuid;primaryKey"`
+	CodeHash      string   `gorm:"size:64;uniqueIndex;not null"`
+	ClientID      string   `gorm:"type:uuid;index;not null"`
+	UserID        string   `gorm:"type:uuid;index;not null"`
+	RedirectURI   string   `gorm:"size:500;not null"`
+	Scopes        []string `gorm:"serializer:json;type:text"`
+	CodeChallenge string   `gorm:"size:128;not null"` // PKCE S256
+	ExpiresAt     time.Time
+	UsedAt        *time.Time
+	CreatedAt     time.Time
+}
+
+func (OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+func (c *OAuthAuthorizationCode) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OAuth grant tipleri
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+)
+
+// OAuthClient API'ye erişen üçüncü parti uygulama. ID client_id olarak
+// kullanılır. Public client'lar (SPA, mobil) secret tutamaz; sadece PKCE'li
+// authorization code grant'ı kullanabilirler.
+type OAuthClient struct {
+	ID           string   `gorm:"type:uuid;primaryKey"`
+	Name         string   `gorm:"size:100;not null"`
+	SecretHash   string   `gorm:"size:64"` // public client'larda boş
+	Public       bool     `gorm:"not null;default:false"`
+	RedirectURIs []string `gorm:"serializer:json;type:text"`
+	GrantTypes   []string `gorm:"serializer:json;type:text"`
+	// Scopes client'ın isteyebileceği izinler (permission adları).
+	Scopes    []string `gorm:"serializer:json;type:text"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+func (c *OAuthClient) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (c *OAuthClient) AllowsGrant(grant string) bool {
+	return slices.Contains(c.GrantTypes, grant)
+}
+
+// AllowsRedirect redirect_uri'yi kayıtlı adreslerle birebir karşılaştırır.
+func (c *OAuthClient) AllowsRedirect(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OAuthConsent kullanıcının bir client'a verdiği scope'lar. Aynı scope'lar
+// tekrar istendiğinde onay ekranı atlanır.
+type OAuthConsent struct {
+	ID        string   `gorm:"type:uuid;primaryKey"`
+	UserID    string   `gorm:"type:uuid;not null;uniqueIndex:idx_oauth_consent_user_client"`
+	ClientID  string   `gorm:"type:uuid;not null;uniqueIndex:idx_oauth_consent_user_client"`
+	Scopes    []string `gorm:"serializer:json;type:text"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+func (c *OAuthConsent) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
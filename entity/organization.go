@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Organization bir müşteri (tenant). Tenant'a ait tablolar organization_id
+// kolonuyla buna bağlanır; bkz. tenant.Scoped.
+type Organization struct {
+	ID        string `gorm:"type:uuid;primaryKey"`
+	Name      string `gorm:"size:100;not null"`
+	Slug      string `gorm:"size:64;uniqueIndex;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (o *Organization) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.ID == "" {
+		o.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// UserOrganization kullanıcının üye olduğu organizasyon ve oradaki rolü.
+// Tablo değildir; üyeliklerle join edilmiş sorgu sonucudur.
+type UserOrganization struct {
+	Organization
+	Role string
+}
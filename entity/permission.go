@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// Permission adları "<kaynak>:<aksiyon>" formatındadır.
+const (
+	PermUsersRead   = "users:read"
+	PermUsersCreate = "users:create"
+	PermUsersUpdate = "users:update"
+	PermUsersDelete = "users:delete"
+	PermRolesAssign = "roles:assign"
+	// PermUsersImpersonate destek ekibinin uygulamayı bir kullanıcı gibi görmesini sağlar.
+	PermUsersImpersonate = "users:impersonate"
+	// PermOAuthClientsManage üçüncü parti OAuth uygulamalarını kaydetme/silme.
+	PermOAuthClientsManage = "oauth_clients:manage"
+)
+
+// AllPermissions seed sırasında oluşturulan ve admin rolüne verilen tüm izinler.
+var AllPermissions = []string{
+	PermUsersRead,
+	PermUsersCreate,
+	PermUsersUpdate,
+	PermUsersDelete,
+	PermRolesAssign,
+	PermUsersImpersonate,
+	PermOAuthClientsManage,
+}
+
+type Permission struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"size:100;uniqueIndex;not null" json:"name"`
+	Description string    `gorm:"size:255" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PhoneVerification SMS ile gönderilen tek kullanımlık doğrulama kodu.
+// Kod sadece SMS'te bulunur, veritabanında hash'i saklanır. Kod gönderildiği
+// numaraya bağlıdır; numara değişirse geçersiz olur.
+type PhoneVerification struct {
+	ID        string    `gorm:"type:uuid;primaryKey"`
+	UserID    string    `gorm:"type:uuid;index;not null"`
+	Phone     string    `gorm:"size:16;not null"`
+	CodeHash  string    `gorm:"size:64;not null"`
+	Attempts  int       `gorm:"not null;default:0"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (v *PhoneVerification) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	return nil
+}
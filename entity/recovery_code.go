@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// RecoveryCode TOTP cihazı kaybedildiğinde kullanılan tek kullanımlık kod.
+// Kodun kendisi değil SHA-256 hash'i saklanır.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	UserID    string `gorm:"type:uuid;index;not null"`
+	CodeHash  string `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefreshToken tek kullanımlık, rotasyona tabi refresh token kaydı.
+// Ham token asla saklanmaz, sadece SHA-256 hash'i tutulur.
+// Aynı login'den türeyen tüm token'lar aynı FamilyID'yi paylaşır.
+type RefreshToken struct {
+	ID         string    `gorm:"type:uuid;primaryKey"`
+	UserID     string    `gorm:"type:uuid;index;not null"`
+	FamilyID   string    `gorm:"type:uuid;index;not null"`
+	TokenHash  string    `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt  time.Time `gorm:"index"`
+	RevokedAt  *time.Time
+	ReplacedBy *string `gorm:"type:uuid"`
+	CreatedAt  time.Time
+}
+
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.FamilyID == "" {
+		t.FamilyID = t.ID
+	}
+	return nil
+}
+
+// Rotated token daha önce kullanılıp yenisiyle değiştirildiyse true döner.
+func (t *RefreshToken) Rotated() bool {
+	return t.ReplacedBy != nil
+}
+
+func (t *RefreshToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// RevokedToken süresi dolmadan geçersiz kılınan access token kaydı.
+// Key "jti:<id>" (tek token) veya "user:<id>" (kullanıcının o ana kadar aldığı tüm token'lar) olabilir.
+type RevokedToken struct {
+	Key       string    `gorm:"primaryKey;size:255"`
+	RevokedAt time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session her login'de açılan oturum. Refresh token ailesinin FamilyID'si ve
+// access token'lardaki "sid" claim'i session ID'sidir.
+type Session struct {
+	ID        string `gorm:"type:uuid;primaryKey"`
+	UserID    string `gorm:"type:uuid;index;not null"`
+	Device    string `gorm:"size:100"`
+	IP        string `gorm:"size:50"`
+	UserAgent string `gorm:"size:500"`
+	// OrganizationID oturumun aktif organizasyonu; access token'lara "org" olarak yazılır.
+	OrganizationID *string `gorm:"type:uuid"`
+	CreatedAt      time.Time
+	LastSeenAt     time.Time
+	RevokedAt      *time.Time
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
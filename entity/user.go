@@ -1,48 +1,106 @@
 package entity
 
 import (
+	"go-initial-project/hashing"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        string         `gorm:"type:uuid;primaryKey" json:"id"`
-	FirstName string         `json:"first_name"`
-	LastName  string         `json:"last_name"`
-	Email     string         `gorm:"unique" json:"email"`
-	Password  string         `json:"-"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 string         `gorm:"type:uuid;primaryKey" json:"id"`
+	FirstName          string         `json:"first_name"`
+	LastName           string         `json:"last_name"`
+	Email              string         `gorm:"unique" json:"email"`
+	PasswordHash       string         `gorm:"column:password" json:"-"` // SetPassword ile atanır
+	EmailVerifiedAt    *time.Time     `json:"email_verified_at"`
+	VerificationSentAt *time.Time     `json:"-"`
+	Phone              string         `gorm:"size:16" json:"phone"` // E.164, ör. +905551112233
+	PhoneVerifiedAt    *time.Time     `json:"phone_verified_at"`
+	TOTPSecret         string         `gorm:"size:255" json:"-"` // şifreli (encryption.Cipher)
+	TOTPEnabledAt      *time.Time     `json:"-"`
+	TOTPLastStep       int64          `json:"-"` // aynı kodun tekrar kullanılmasını engeller
+	Roles              []Role         `gorm:"many2many:user_roles" json:"roles,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) PhoneVerified() bool {
+	return u.PhoneVerifiedAt != nil
+}
+
+// ApplyProfile boş olmayan ve değişen alanları user'a yazar, değişen
+// kolonları döner. E-posta veya telefon değişirse doğrulaması sıfırlanır.
+func (u *User) ApplyProfile(firstName, lastName, email, phone string) map[string]interface{} {
+	values := map[string]interface{}{}
+	if firstName != "" && firstName != u.FirstName {
+		u.FirstName = firstName
+		values["first_name"] = firstName
+	}
+	if lastName != "" && lastName != u.LastName {
+		u.LastName = lastName
+		values["last_name"] = lastName
+	}
+	if email != "" && !strings.EqualFold(email, u.Email) {
+		u.Email = email
+		u.EmailVerifiedAt = nil
+		u.VerificationSentAt = nil
+		values["email"] = email
+		values["email_verified_at"] = nil
+		values["verification_sent_at"] = nil
+	}
+	if phone != "" && phone != u.Phone {
+		u.Phone = phone
+		u.PhoneVerifiedAt = nil
+		values["phone"] = phone
+		values["phone_verified_at"] = nil
+	}
+	return values
+}
+
+func (u *User) MFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 	if u.ID == "" {
 		u.ID = uuid.New().String()
 	}
+	return nil
+}
 
-	if u.Password != "" {
-		hashed, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
-		if err != nil {
-			return err
-		}
-		u.Password = string(hashed)
+// SetPassword şifreyi verilen hasher ile hash'leyip PasswordHash'e yazar.
+// Şifre sadece bu metotla atanır; kaydetme sırasında hook'lar şifreye dokunmaz.
+func (u *User) SetPassword(h *hashing.Manager, plain string) error {
+	hashed, err := h.Hash(plain)
+	if err != nil {
+		return err
 	}
+	u.PasswordHash = hashed
 	return nil
 }
 
-func (u *User) BeforeUpdate(tx *gorm.DB) (err error) {
-	if u.Password != "" {
-		if len(u.Password) < 60 {
-			hashed, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
-			if err != nil {
-				return err
-			}
-			u.Password = string(hashed)
-		}
+// CheckPassword şifreyi doğrular. needsRehash true ise hash eski bir
+// algoritma veya parametreyle üretilmiştir ve yenilenmelidir.
+func (u *User) CheckPassword(h *hashing.Manager, plain string) (ok bool, needsRehash bool) {
+	if !u.HasPassword() {
+		return false, false
 	}
-	return nil
+	ok, needsRehash, err := h.Verify(plain, u.PasswordHash)
+	if err != nil {
+		return false, false
+	}
+	return ok, needsRehash
+}
+
+// HasPassword OIDC ile açılan hesaplarda şifre olmayabilir.
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != ""
 }
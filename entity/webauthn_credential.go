@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebAuthnCredential kullanıcının kaydettiği passkey. CredentialID
+// authenticator'ın ürettiği kimliktir; sadece public key saklanır.
+type WebAuthnCredential struct {
+	ID              string   `gorm:"type:uuid;primaryKey"`
+	UserID          string   `gorm:"type:uuid;index;not null"`
+	Name            string   `gorm:"size:100;not null"`
+	CredentialID    []byte   `gorm:"uniqueIndex;not null"`
+	PublicKey       []byte   `gorm:"not null"` // COSE formatında
+	AttestationType string   `gorm:"size:32"`
+	AAGUID          []byte   // authenticator modeli
+	SignCount       uint32   `gorm:"not null;default:0"`
+	Transports      []string `gorm:"serializer:json;type:text"` // usb, nfc, ble, internal, hybrid
+	// BackupEligible passkey'in cihazlar arası senkronize edilebildiğini
+	// gösterir ve kayıttan sonra değişmemelidir.
+	BackupEligible bool `gorm:"not null;default:false"`
+	BackupState    bool `gorm:"not null;default:false"`
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+func (WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
+
+func (c *WebAuthnCredential) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	return nil
+}
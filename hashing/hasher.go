@@ -0,0 +1,49 @@
+package hashing
+
+import "errors"
+
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Hasher tek bir algoritma için şifre hash'leme. Çıktı algoritmayı ve
+// parametreleri içeren PHC/modular crypt formatındadır; böylece farklı
+// algoritmalarla üretilmiş hash'ler aynı kolonda yan yana durabilir.
+type Hasher interface {
+	// Recognizes hash'in bu algoritmaya ait olup olmadığını söyler.
+	Recognizes(encoded string) bool
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	// NeedsRehash hash güncel parametrelerle üretilmemişse true döner.
+	NeedsRehash(encoded string) bool
+}
+
+// Manager yeni hash'leri tercih edilen algoritmayla üretir, eski hash'leri
+// tanıdığı her algoritmayla doğrular.
+type Manager struct {
+	preferred Hasher
+	hashers   []Hasher
+}
+
+func NewManager(preferred Hasher, legacy ...Hasher) *Manager {
+	return &Manager{preferred: preferred, hashers: append([]Hasher{preferred}, legacy...)}
+}
+
+func (m *Manager) Hash(password string) (string, error) {
+	return m.preferred.Hash(password)
+}
+
+// Verify şifreyi kontrol eder. ok ise ve hash eski bir algoritma veya
+// parametreyle üretilmişse needsRehash true döner; çağıran taraf şifreyi
+// yeniden hash'leyip kaydetmelidir.
+func (m *Manager) Verify(password, encoded string) (ok bool, needsRehash bool, err error) {
+	for _, h := range m.hashers {
+		if !h.Recognizes(encoded) {
+			continue
+		}
+		ok, err := h.Verify(password, encoded)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, h != m.preferred || h.NeedsRehash(encoded), nil
+	}
+	return false, false, ErrUnknownHash
+}
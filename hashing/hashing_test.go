@@ -0,0 +1,172 @@
+package hashing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestArgon2id(t *testing.T, memory, iterations uint32) *Argon2id {
+	t.Helper()
+	a, err := NewArgon2id(memory, iterations, 1, 16, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func newTestBcrypt(t *testing.T, cost int) *Bcrypt {
+	t.Helper()
+	b, err := NewBcrypt(cost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	a := newTestArgon2id(t, 64, 1)
+
+	encoded, err := a.Hash("s3cret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("encoded = %q, want PHC format with the configured parameters", encoded)
+	}
+
+	p, err := decodeArgon2(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.memory != 64 || p.iterations != 1 || p.parallelism != 1 || len(p.salt) != 16 || len(p.key) != 32 {
+		t.Fatalf("decoded params = %+v", p)
+	}
+
+	if ok, err := a.Verify("s3cret-password", encoded); err != nil || !ok {
+		t.Fatalf("Verify(correct) = %v, %v", ok, err)
+	}
+	if ok, err := a.Verify("wrong-password", encoded); err != nil || ok {
+		t.Fatalf("Verify(wrong) = %v, %v", ok, err)
+	}
+	if a.NeedsRehash(encoded) {
+		t.Fatal("fresh hash needs rehash")
+	}
+
+	// Her hash kendi salt'ını kullanır.
+	again, err := a.Hash("s3cret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == encoded {
+		t.Fatal("two hashes of the same password are identical")
+	}
+}
+
+func TestArgon2idRejectsMalformedHashes(t *testing.T) {
+	a := newTestArgon2id(t, 64, 1)
+	valid, err := a.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, "$")
+
+	tests := map[string]string{
+		"empty":          "",
+		"too few parts":  "$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"wrong variant":  strings.Replace(valid, "$argon2id$", "$argon2i$", 1),
+		"wrong version":  strings.Replace(valid, "$v=19$", "$v=16$", 1),
+		"bad params":     strings.Replace(valid, "m=64,t=1,p=1", "m=x,t=1,p=1", 1),
+		"bad salt":       strings.Join([]string{"", parts[1], parts[2], parts[3], "!!!", parts[5]}, "$"),
+		"bad key":        strings.Join([]string{"", parts[1], parts[2], parts[3], parts[4], "!!!"}, "$"),
+		"empty key":      strings.Join([]string{"", parts[1], parts[2], parts[3], parts[4], ""}, "$"),
+		"bcrypt instead": "$2a$04$abcdefghijklmnopqrstuu5Zl1c3XgJ9m0i6ZrHcTzM4Yc0dX3Jm.",
+	}
+	for name, encoded := range tests {
+		if _, err := a.Verify("password", encoded); !errors.Is(err, errArgon2Format) {
+			t.Errorf("%s: err = %v, want errArgon2Format", name, err)
+		}
+		if !a.NeedsRehash(encoded) {
+			t.Errorf("%s: malformed hash does not need rehash", name)
+		}
+	}
+}
+
+func TestArgon2idNeedsRehashOnParameterChange(t *testing.T) {
+	old := newTestArgon2id(t, 64, 1)
+	encoded, err := old.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]*Argon2id{
+		"memory":      newTestArgon2id(t, 128, 1),
+		"iterations":  newTestArgon2id(t, 64, 2),
+		"salt length": {Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 32, KeyLength: 32},
+		"key length":  {Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 64},
+	}
+	for name, current := range tests {
+		if !current.NeedsRehash(encoded) {
+			t.Errorf("%s changed: NeedsRehash = false", name)
+		}
+		// Eski parametrelerle üretilmiş hash hâlâ doğrulanır.
+		if ok, err := current.Verify("password", encoded); err != nil || !ok {
+			t.Errorf("%s changed: Verify = %v, %v", name, ok, err)
+		}
+	}
+}
+
+func TestManagerVerify(t *testing.T) {
+	argon := newTestArgon2id(t, 64, 1)
+	bcrypt := newTestBcrypt(t, 4)
+	m := NewManager(argon, bcrypt)
+
+	legacy, err := bcrypt.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := m.Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outdated, err := newTestArgon2id(t, 32, 1).Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		password        string
+		encoded         string
+		wantOK          bool
+		wantNeedsRehash bool
+		wantErr         error
+	}{
+		{"preferred", "password", current, true, false, nil},
+		{"preferred wrong password", "wrong", current, false, false, nil},
+		{"legacy bcrypt", "password", legacy, true, true, nil},
+		{"legacy bcrypt wrong password", "wrong", legacy, false, false, nil},
+		{"outdated parameters", "password", outdated, true, true, nil},
+		{"unknown format", "password", "plaintext", false, false, ErrUnknownHash},
+	}
+	for _, tt := range tests {
+		ok, needsRehash, err := m.Verify(tt.password, tt.encoded)
+		if ok != tt.wantOK || needsRehash != tt.wantNeedsRehash || !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Verify = %v, %v, %v; want %v, %v, %v",
+				tt.name, ok, needsRehash, err, tt.wantOK, tt.wantNeedsRehash, tt.wantErr)
+		}
+	}
+}
+
+func TestBcryptNeedsRehashOnCostChange(t *testing.T) {
+	encoded, err := newTestBcrypt(t, 4).Hash("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newTestBcrypt(t, 4).NeedsRehash(encoded) {
+		t.Fatal("same cost needs rehash")
+	}
+	if !newTestBcrypt(t, 5).NeedsRehash(encoded) {
+		t.Fatal("changed cost does not need rehash")
+	}
+}
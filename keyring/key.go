@@ -0,0 +1,124 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+var ErrUnsupportedKey = errors.New("unsupported key type")
+
+// Key tek bir imzalama/doğrulama anahtarı. signKey nil ise anahtar sadece
+// doğrulama için kullanılır (rotasyonda emekliye ayrılmış anahtarlar).
+type Key struct {
+	ID        string
+	Algorithm string
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+func NewHMACKey(id string, secret []byte) (*Key, error) {
+	if len(secret) < 16 {
+		return nil, fmt.Errorf("hmac key %q: secret must be at least 16 bytes", id)
+	}
+	return &Key{ID: id, Algorithm: HS256, signKey: secret, verifyKey: secret}, nil
+}
+
+func NewRSAKey(id string, private *rsa.PrivateKey) (*Key, error) {
+	if private.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("rsa key %q: must be at least 2048 bits", id)
+	}
+	return &Key{ID: id, Algorithm: RS256, signKey: private, verifyKey: &private.PublicKey}, nil
+}
+
+func NewEdDSAKey(id string, private ed25519.PrivateKey) *Key {
+	return &Key{ID: id, Algorithm: EdDSA, signKey: private, verifyKey: private.Public()}
+}
+
+// NewPublicKey sadece doğrulama yapabilen bir anahtar oluşturur.
+func NewPublicKey(id string, public crypto.PublicKey) (*Key, error) {
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return &Key{ID: id, Algorithm: RS256, verifyKey: pub}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: id, Algorithm: EdDSA, verifyKey: pub}, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// ParsePrivateKeyPEM PKCS#8 (RSA/Ed25519) veya PKCS#1 (RSA) private key okur.
+func ParsePrivateKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %q: invalid PEM", id)
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		rsaKey, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if rsaErr != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		private = rsaKey
+	}
+
+	switch priv := private.(type) {
+	case *rsa.PrivateKey:
+		return NewRSAKey(id, priv)
+	case ed25519.PrivateKey:
+		return NewEdDSAKey(id, priv), nil
+	default:
+		return nil, fmt.Errorf("key %q: %w", id, ErrUnsupportedKey)
+	}
+}
+
+// ParsePublicKeyPEM PKIX veya PKCS#1 public key okur.
+func ParsePublicKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %q: invalid PEM", id)
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		rsaKey, rsaErr := x509.ParsePKCS1PublicKey(block.Bytes)
+		if rsaErr != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		public = rsaKey
+	}
+	return NewPublicKey(id, public)
+}
+
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// Public anahtar JWKS ile yayınlanabilir mi? HMAC secret'ları asla yayınlanmaz.
+func (k *Key) Public() bool {
+	return k.Algorithm != HS256
+}
+
+func (k *Key) Method() jwt.SigningMethod {
+	switch k.Algorithm {
+	case RS256:
+		return jwt.SigningMethodRS256
+	case EdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
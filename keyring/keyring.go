@@ -0,0 +1,141 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoActiveKey = errors.New("no active signing key")
+	ErrUnknownKey  = errors.New("unknown key id")
+)
+
+// Keyring imzalama için tek bir aktif anahtar, doğrulama için ise birden fazla
+// anahtar tutar. Rotasyon sırasında eski anahtarla imzalanmış token'lar
+// süreleri dolana kadar doğrulanmaya devam eder.
+type Keyring struct {
+	mu       sync.RWMutex
+	keys     map[string]*Key
+	activeID string
+}
+
+func New() *Keyring {
+	return &Keyring{keys: make(map[string]*Key)}
+}
+
+func (r *Keyring) Add(key *Key) error {
+	if key.ID == "" {
+		return errors.New("key id required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.keys[key.ID]; exists {
+		return fmt.Errorf("duplicate key id %q", key.ID)
+	}
+	r.keys[key.ID] = key
+	return nil
+}
+
+// SetActive yeni token'ların imzalanacağı anahtarı değiştirir.
+func (r *Keyring) SetActive(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	if !key.CanSign() {
+		return fmt.Errorf("key %q has no private part", id)
+	}
+	r.activeID = id
+	return nil
+}
+
+// Remove anahtarı tamamen kaldırır; onunla imzalanmış token'lar artık doğrulanmaz.
+func (r *Keyring) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id == r.activeID {
+		return errors.New("cannot remove active key")
+	}
+	delete(r.keys, id)
+	return nil
+}
+
+func (r *Keyring) Active() (*Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[r.activeID]
+	if !ok {
+		return nil, ErrNoActiveKey
+	}
+	return key, nil
+}
+
+// Sign claim'leri aktif anahtarla imzalar ve header'a kid ekler.
+func (r *Keyring) Sign(claims jwt.Claims) (string, error) {
+	key, err := r.Active()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signKey)
+}
+
+// Keyfunc jwt.Parse için; kid'e göre anahtarı seçer ve token'ın algoritmasının
+// anahtarın algoritmasıyla aynı olmasını zorunlu kılar (alg confusion).
+func (r *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid header")
+	}
+
+	r.mu.RLock()
+	key, ok := r.keys[kid]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), kid)
+	}
+	return key.verifyKey, nil
+}
+
+// Algorithms keyring'deki anahtarların kullandığı algoritmalar.
+func (r *Keyring) Algorithms() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	seen := map[string]bool{}
+	var algs []string
+	for _, key := range r.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algs = append(algs, key.Algorithm)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// JWKS yayınlanabilir tüm public anahtarları döner.
+func (r *Keyring) JWKS() JWKSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range r.keys {
+		if !key.Public() {
+			continue
+		}
+		if jwk, ok := key.JWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
@@ -0,0 +1,221 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func generateKey(t *testing.T, id, algorithm string) *Key {
+	t.Helper()
+	if algorithm == HS256 {
+		key, err := NewHMACKey(id, []byte("0123456789abcdef0123456789abcdef"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	key, err := Generate(id, algorithm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newRing(t *testing.T, keys ...*Key) *Keyring {
+	t.Helper()
+	ring := New()
+	for _, key := range keys {
+		if err := ring.Add(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ring.SetActive(keys[len(keys)-1].ID); err != nil {
+		t.Fatal(err)
+	}
+	return ring
+}
+
+func sign(t *testing.T, ring *Keyring) string {
+	t.Helper()
+	token, err := ring.Sign(jwt.RegisteredClaims{Subject: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func parse(ring *Keyring, token string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, ring.Keyfunc, jwt.WithValidMethods(ring.Algorithms()))
+}
+
+func TestSignUsesActiveKid(t *testing.T) {
+	for _, algorithm := range []string{HS256, RS256, EdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			ring := newRing(t, generateKey(t, "old", algorithm), generateKey(t, "current", algorithm))
+
+			token, err := parse(ring, sign(t, ring))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.Header["kid"] != "current" || token.Method.Alg() != algorithm {
+				t.Fatalf("header = %v, want kid current and alg %s", token.Header, algorithm)
+			}
+		})
+	}
+}
+
+func TestRetiredKeyStillVerifies(t *testing.T) {
+	old := generateKey(t, "2024", RS256)
+	ring := newRing(t, old)
+	issued := sign(t, ring)
+
+	// Rotasyon: yeni anahtar aktif olur, eskisinin sadece public kısmı kalır.
+	retired, err := NewPublicKey("2024", old.verifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated := newRing(t, retired, generateKey(t, "2025", RS256))
+	if retired.CanSign() {
+		t.Fatal("retired key can still sign")
+	}
+
+	if _, err := parse(rotated, issued); err != nil {
+		t.Fatalf("token signed with the retired key rejected: %v", err)
+	}
+	token, err := parse(rotated, sign(t, rotated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Header["kid"] != "2025" {
+		t.Fatalf("signed with kid %v, want 2025", token.Header["kid"])
+	}
+}
+
+func TestUnknownKidRejected(t *testing.T) {
+	ring := newRing(t, generateKey(t, "old", EdDSA), generateKey(t, "current", EdDSA))
+	other := newRing(t, generateKey(t, "ghost", EdDSA))
+
+	if _, err := parse(ring, sign(t, other)); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("err = %v, want ErrUnknownKey", err)
+	}
+
+	issued := sign(t, newRing(t, ring.keys["old"]))
+	if err := ring.Remove("old"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(ring, issued); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("err = %v, want ErrUnknownKey after removal", err)
+	}
+	if err := ring.Remove("current"); err == nil {
+		t.Fatal("active key was removed")
+	}
+}
+
+func TestKeyfuncRejectsAlgorithmConfusion(t *testing.T) {
+	rsaKey := generateKey(t, "rsa", RS256)
+	ring := newRing(t, generateKey(t, "hmac", HS256), rsaKey)
+
+	// RSA public key'i HMAC secret'ı olarak kullanan sahte token.
+	public := x509.MarshalPKCS1PublicKey(rsaKey.verifyKey.(*rsa.PublicKey))
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "admin"})
+	forged.Header["kid"] = "rsa"
+	signed, err := forged.SignedString(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(ring, signed); err == nil {
+		t.Fatal("HS256 token accepted for an RS256 kid")
+	}
+
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{Subject: "admin"})
+	unsigned.Header["kid"] = "hmac"
+	none, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(ring, none); err == nil {
+		t.Fatal("alg=none token accepted")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	ring := newRing(t,
+		generateKey(t, "b-ed", EdDSA),
+		generateKey(t, "c-hmac", HS256),
+		generateKey(t, "a-rsa", RS256),
+	)
+
+	set := ring.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("jwks = %+v, want only the RSA and Ed25519 keys", set)
+	}
+	rsaJWK, edJWK := set.Keys[0], set.Keys[1]
+	if rsaJWK.Kid != "a-rsa" || rsaJWK.Kty != "RSA" || rsaJWK.Alg != RS256 || rsaJWK.Use != "sig" ||
+		rsaJWK.E != "AQAB" || rsaJWK.N == "" || rsaJWK.X != "" {
+		t.Fatalf("rsa jwk = %+v", rsaJWK)
+	}
+	if edJWK.Kid != "b-ed" || edJWK.Kty != "OKP" || edJWK.Crv != "Ed25519" || edJWK.Alg != EdDSA ||
+		len(edJWK.X) != 43 || edJWK.N != "" {
+		t.Fatalf("ed25519 jwk = %+v", edJWK)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	active := generateKey(t, "active", EdDSA)
+	private, err := x509.MarshalPKCS8PrivateKey(active.signKey.(ed25519.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retired := generateKey(t, "retired", RS256)
+	public, err := x509.MarshalPKIXPublicKey(retired.verifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, filepath.Join(dir, "active.pem"), "PRIVATE KEY", private)
+	writePEM(t, filepath.Join(dir, "retired.pub.pem"), "PUBLIC KEY", public)
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring := New()
+	for _, key := range keys {
+		if err := ring.Add(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ring.SetActive("retired"); err == nil {
+		t.Fatal("public-only key became active")
+	}
+	if err := ring.SetActive("active"); err != nil {
+		t.Fatal(err)
+	}
+	if algs := ring.Algorithms(); len(algs) != 2 || algs[0] != EdDSA || algs[1] != RS256 {
+		t.Fatalf("algorithms = %v", algs)
+	}
+
+	writePEM(t, filepath.Join(dir, "broken.pem"), "PRIVATE KEY", []byte("garbage"))
+	if _, err := LoadDir(dir); err == nil {
+		t.Fatal("malformed key loaded")
+	}
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,67 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadDir dizindeki anahtarları okur:
+//   - <kid>.pem      imzalayabilen private key (RSA veya Ed25519)
+//   - <kid>.pub.pem  sadece doğrulama için public key (emekli anahtarlar)
+func LoadDir(dir string) ([]*Key, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*Key
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		var key *Key
+		if strings.HasSuffix(name, ".pub.pem") {
+			key, err = ParsePublicKeyPEM(strings.TrimSuffix(name, ".pub.pem"), data)
+		} else {
+			key, err = ParsePrivateKeyPEM(strings.TrimSuffix(name, ".pem"), data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Generate geliştirme ortamı için geçici bir anahtar üretir. Process yeniden
+// başladığında kaybolur, production'da LoadDir kullanılmalı.
+func Generate(id, algorithm string) (*Key, error) {
+	switch algorithm {
+	case RS256:
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		return NewRSAKey(id, private)
+	case EdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return NewEdDSAKey(id, private), nil
+	default:
+		return nil, fmt.Errorf("cannot generate %s key", algorithm)
+	}
+}
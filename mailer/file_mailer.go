@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer her e-postayı dizine ayrı bir .eml dosyası olarak yazar.
+type FileMailer struct {
+	From string
+	Dir  string
+}
+
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileMailer{From: from, Dir: dir}, nil
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), uuid.NewString()[:8])
+	return os.WriteFile(filepath.Join(m.Dir, name), buildMessage(m.From, msg, now), 0o644)
+}
@@ -0,0 +1,17 @@
+package mailer
+
+import "log"
+
+// LogMailer e-postaları sadece log'a yazar.
+type LogMailer struct {
+	From string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{From: from}
+}
+
+func (m *LogMailer) Send(msg Message) error {
+	log.Printf("📧 mail from=%s to=%s subject=%q\n%s", m.From, msg.To, msg.Subject, msg.Body)
+	return nil
+}
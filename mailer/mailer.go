@@ -0,0 +1,14 @@
+package mailer
+
+// Message gönderilecek düz metin e-posta.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer e-posta gönderim sürücüsü. Geliştirme ortamında log veya file
+// sürücüsü, production'da smtp kullanılır.
+type Mailer interface {
+	Send(msg Message) error
+}
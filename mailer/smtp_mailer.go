@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type SMTPMailer struct {
+	From     string
+	Host     string
+	Port     string
+	Username string
+	Password string
+}
+
+func NewSMTPMailer(from, host, port, username, password string) *SMTPMailer {
+	return &SMTPMailer{From: from, Host: host, Port: port, Username: username, Password: password}
+}
+
+func (m *SMTPMailer) Send(msg Message) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	addr := net.JoinHostPort(m.Host, m.Port)
+	return smtp.SendMail(addr, auth, m.From, []string{msg.To}, buildMessage(m.From, msg, time.Now()))
+}
+
+// buildMessage RFC 5322 formatında basit bir düz metin e-posta oluşturur.
+func buildMessage(from string, msg Message, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	buf.WriteString(msg.Body)
+	return buf.Bytes()
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"log"
+	"time"
+
 	"go-initial-project/config"
 	"go-initial-project/controller"
 	docs "go-initial-project/docs"
+	"go-initial-project/entity"
 	"go-initial-project/repository"
 	"go-initial-project/router"
 	"go-initial-project/service"
+	"go-initial-project/tenant"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -19,22 +24,111 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	config.LoadEnv()
 
 	db := config.ConnectDB()
 
+	keys, err := config.NewKeyring()
+	if err != nil {
+		log.Fatal("Failed to load JWT keys:", err)
+	}
+
+	mail, err := config.NewMailer()
+	if err != nil {
+		log.Fatal("Failed to configure mailer:", err)
+	}
+
+	smsSender, err := config.NewSMSSender()
+	if err != nil {
+		log.Fatal("Failed to configure SMS sender:", err)
+	}
+
+	cipher, err := config.NewCipher()
+	if err != nil {
+		log.Fatal("Failed to configure encryption:", err)
+	}
+
+	hasher, err := config.NewPasswordHasher()
+	if err != nil {
+		log.Fatal("Failed to configure password hashing:", err)
+	}
+
+	passwordPolicy, err := config.NewPasswordPolicy()
+	if err != nil {
+		log.Fatal("Failed to configure password policy:", err)
+	}
+
+	webAuthn, err := config.NewWebAuthn()
+	if err != nil {
+		log.Fatal("Failed to configure WebAuthn:", err)
+	}
+
 	userRepo := repository.NewUserRepository(db)
 	activityRepo := repository.NewActivityRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	revokedTokenRepo := repository.NewRevokedTokenRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	passwordResetRepo := repository.NewPasswordResetTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	oauthClientRepo := repository.NewOAuthClientRepository(db)
+	oauthCodeRepo := repository.NewOAuthAuthorizationCodeRepository(db)
+	oauthConsentRepo := repository.NewOAuthConsentRepository(db)
+	webauthnCredentialRepo := repository.NewWebAuthnCredentialRepository(db)
+	phoneVerificationRepo := repository.NewPhoneVerificationRepository(db)
+	organizationRepo := repository.NewOrganizationRepository(db)
+	membershipRepo := repository.NewMembershipRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
 
-	userService := service.NewUserService(userRepo)
+	userService := service.NewUserService(userRepo, roleRepo, hasher, passwordPolicy)
+	roleService := service.NewRoleService(roleRepo)
 	activityService := service.NewActivityService(activityRepo)
+	tenant.SetAuditor(activityService.LogScopeBypass)
+	revocations := newRevocationStore(revokedTokenRepo)
+	sessionService := service.NewSessionService(sessionRepo, refreshTokenRepo, revocations)
+	tokenService := service.NewTokenService(keys, userService, roleService, refreshTokenRepo, apiKeyRepo, revocations, sessionService)
+
+	passwordResetService := service.NewPasswordResetService(userService, tokenService, activityService, passwordResetRepo, mail)
+	emailVerificationService := service.NewEmailVerificationService(userService, tokenService, activityService, mail)
+	phoneVerificationService := service.NewPhoneVerificationService(phoneVerificationRepo, userService, activityService, smsSender)
+	loginThrottleService := service.NewLoginThrottleService(loginThrottleRepo, userService, activityService)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userService, roleService, activityService)
+	oidcService := service.NewOIDCService(config.AppConfig.OIDC.Providers, userService, identityRepo, activityService, cipher)
+	passwordService := service.NewPasswordService(userService, sessionService, activityService, loginThrottleService, passwordResetRepo, apiKeyRepo)
+	webauthnService := service.NewWebAuthnService(webAuthn, webauthnCredentialRepo, userService, tokenService, activityService, cipher)
+	mfaService := service.NewMFAService(userService, tokenService, activityService, loginThrottleService, recoveryCodeRepo, cipher, webauthnService)
+	impersonationService := service.NewImpersonationService(userService, roleService, tokenService, activityService)
+	magicLinkService := service.NewMagicLinkService(userService, tokenService, loginThrottleService, activityService, mail)
+	oauthService := service.NewOAuthService(oauthClientRepo, oauthCodeRepo, oauthConsentRepo, userService, tokenService, activityService)
+	organizationService := service.NewOrganizationService(organizationRepo, membershipRepo, tokenService, activityService)
+	invitationService := service.NewInvitationService(invitationRepo, organizationService, userService, activityService, mail)
 
-	userController := controller.NewUserController(userService)
-	authController := controller.NewAuthController(userService)
+	seedRoles(roleService, userService)
+
+	userController := controller.NewUserController(userService, roleService, tokenService)
+	authController := controller.NewAuthController(userService, tokenService, passwordResetService, emailVerificationService, mfaService, loginThrottleService, sessionService, passwordService, webauthnService, invitationService)
+	mfaController := controller.NewMFAController(tokenService, mfaService)
+	apiKeyController := controller.NewAPIKeyController(tokenService, apiKeyService)
+	oidcController := controller.NewOIDCController(tokenService, mfaService, oidcService)
+	sessionController := controller.NewSessionController(tokenService, sessionService)
+	adminController := controller.NewAdminController(tokenService, impersonationService)
+	magicLinkController := controller.NewMagicLinkController(tokenService, mfaService, magicLinkService)
+	profileController := controller.NewProfileController(userService, tokenService, emailVerificationService, phoneVerificationService)
+	oauthController := controller.NewOAuthController(tokenService, oauthService)
+	oauthClientController := controller.NewOAuthClientController(tokenService, oauthService)
+	organizationController := controller.NewOrganizationController(tokenService, organizationService)
+	invitationController := controller.NewInvitationController(tokenService, organizationService, invitationService)
 
 	// Router
-	r := router.SetupRouter(activityService, userController, authController)
+	r := router.SetupRouter(activityService, apiKeyService, userController, authController, mfaController, apiKeyController, oidcController, sessionController, adminController, magicLinkController, oauthController, oauthClientController, profileController, organizationController, invitationController)
+	controller.NewWellKnownController(keys).RegisterRoutes(&r.RouterGroup)
 
 	docs.SwaggerInfo.BasePath = "/api"
 
@@ -45,3 +139,30 @@ func main() {
 
 	r.Run(":" + config.AppConfig.App.Port)
 }
+
+func newRevocationStore(repo *repository.RevokedTokenRepository) service.RevocationStore {
+	if config.AppConfig.JWT.RevocationStore == "memory" {
+		return service.NewMemoryRevocationStore(time.Minute)
+	}
+	return service.NewDBRevocationStore(repo, 10*time.Minute)
+}
+
+// seedRoles varsayılan rolleri oluşturur ve ADMIN_EMAIL ile kayıtlı kullanıcıya admin rolü verir.
+func seedRoles(roleService *service.RoleService, userService *service.UserService) {
+	if err := roleService.SeedDefaults(); err != nil {
+		log.Fatal("Failed to seed roles:", err)
+	}
+
+	email := config.AppConfig.App.AdminEmail
+	if email == "" {
+		return
+	}
+	admin, err := userService.FindByEmail(email)
+	if err != nil {
+		log.Printf("⚠️ admin user %s not found, register it and restart to grant admin role", email)
+		return
+	}
+	if err := roleService.AssignRoles(admin.ID, entity.RoleAdmin); err != nil {
+		log.Fatal("Failed to assign admin role:", err)
+	}
+}
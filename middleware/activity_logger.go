@@ -2,10 +2,16 @@ package middleware
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"go-initial-project/entity"
+	"go-initial-project/principal"
 	"go-initial-project/service"
+	"go-initial-project/tenant"
 	"io/ioutil"
+	"mime"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,24 +27,36 @@ func ActivityLogger(activityService *service.ActivityService) gin.HandlerFunc {
 
 		c.Next()
 
-		var userID *string
-		if val, exists := c.Get("user_id"); exists {
-			str := fmt.Sprintf("%v", val)
-			userID = &str
+		var userID, actorID *string
+		if value, exists := c.Get(PrincipalKey); exists {
+			p := value.(*principal.Principal)
+			if p.UserID != "" {
+				userID = &p.UserID
+			}
+			if p.ActorID != "" {
+				actorID = &p.ActorID
+			}
+		}
+
+		var organizationID *string
+		if id, ok := tenant.FromContext(c.Request.Context()); ok {
+			organizationID = &id
 		}
 
 		path := c.Request.URL.Path
 
 		activity := &entity.Activity{
-			UserID:    userID,
-			Action:    "request",
-			Path:      path,
-			Method:    c.Request.Method,
-			IP:        c.ClientIP(),
-			UserAgent: c.Request.UserAgent(),
-			Request:   string(bodyBytes),
-			Status:    c.Writer.Status(),
-			CreatedAt: time.Now(),
+			UserID:         userID,
+			ActorID:        actorID,
+			OrganizationID: organizationID,
+			Action:         "request",
+			Path:           path,
+			Method:         c.Request.Method,
+			IP:             c.ClientIP(),
+			UserAgent:      c.Request.UserAgent(),
+			Request:        requestBody(path, c.ContentType(), bodyBytes),
+			Status:         c.Writer.Status(),
+			CreatedAt:      time.Now(),
 		}
 
 		if err := activityService.Log(activity); err != nil {
@@ -46,3 +64,76 @@ func ActivityLogger(activityService *service.ActivityService) gin.HandlerFunc {
 		}
 	}
 }
+
+// redactedFields değerleri activity log'una yazılmayan JSON ve form alanları.
+var redactedFields = map[string]bool{
+	"password":         true,
+	"current_password": true,
+	"new_password":     true,
+	"token":            true,
+	"refresh_token":    true,
+	"mfa_token":        true,
+	"invitation_token": true,
+	"code":             true,
+	"code_verifier":    true,
+	"client_secret":    true,
+}
+
+// secretPaths body'si sır taşıyan uçlar. Bu uçlarda ayrıştırılamayan body'ler
+// hiç kaydedilmez.
+var secretPaths = []string{"/api/auth/", "/api/oauth/token", "/api/oauth/revoke", "/api/oauth/introspect", "/api/invitations/accept"}
+
+const redactedValue = "[REDACTED]"
+
+// requestBody log'a yazılacak body'yi döner: JSON ve form body'lerindeki
+// redactedFields alanlarının değerleri maskelenir.
+func requestBody(path, contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			for key := range values {
+				if redactedFields[key] {
+					values[key] = []string{redactedValue}
+				}
+			}
+			return values.Encode()
+		}
+	} else {
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if decoder.Decode(&value) == nil {
+			if out, err := json.Marshal(redactJSON(value)); err == nil {
+				return string(out)
+			}
+		}
+	}
+
+	for _, prefix := range secretPaths {
+		if strings.HasPrefix(path, prefix) {
+			return ""
+		}
+	}
+	return string(body)
+}
+
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if redactedFields[key] {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSON(item)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"errors"
+	"go-initial-project/principal"
+	"go-initial-project/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auth_method context değerleri
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+	// AuthMethodOAuth üçüncü parti OAuth client'ına verilmiş token.
+	AuthMethodOAuth = "oauth"
+)
+
+// APIKeyAuth "Authorization: ApiKey <key>" veya "X-API-Key: <key>" header'ı
+// varsa anahtarı doğrular ve sahibini context'e koyar. Header yoksa isteğe
+// dokunmaz; JWT doğrulaması AuthRequired'a kalır.
+func APIKeyAuth(apiKeyService *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader("X-API-Key")
+		if authHeader := c.GetHeader("Authorization"); raw == "" && strings.HasPrefix(authHeader, "ApiKey ") {
+			raw = strings.TrimPrefix(authHeader, "ApiKey ")
+		}
+		if raw == "" {
+			c.Next()
+			return
+		}
+
+		result, err := apiKeyService.Authenticate(raw)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidAPIKey) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not verify api key"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", result.Key.UserID)
+		c.Set("roles", result.Roles)
+		c.Set("permissions", result.Permissions)
+		c.Set("api_key_id", result.Key.ID)
+		c.Set("auth_method", AuthMethodAPIKey)
+
+		p := &principal.Principal{
+			Kind:        principal.KindAPIKey,
+			UserID:      result.Key.UserID,
+			APIKeyID:    result.Key.ID,
+			Roles:       result.Roles,
+			Permissions: result.Permissions,
+			Scopes:      result.Key.Scopes,
+		}
+		p.SetUser(result.User)
+		setPrincipal(c, p)
+		c.Next()
+	}
+}
+
+// RejectAPIKey API anahtarıyla yapılamayacak işlemler (anahtar yönetimi, 2FA,
+// logout) için kullanılır; bu uçlar kullanıcının kendi oturumunu ister.
+// OAuth client'larının token'ları da aynı şekilde reddedilir.
+func RejectAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if method := c.GetString("auth_method"); method == AuthMethodAPIKey || method == AuthMethodOAuth {
+			c.JSON(http.StatusForbidden, gin.H{"error": "this endpoint requires a user session"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
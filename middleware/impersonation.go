@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RejectImpersonation şifre değişikliği, 2FA, API anahtarları gibi hassas
+// işlemleri impersonation token'ıyla yapılamaz hale getirir.
+func RejectImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("actor_id") != "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "this action is not allowed while impersonating a user"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,54 +1,148 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
+	"go-initial-project/principal"
+	"go-initial-project/service"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-var JwtSecret = []byte("super-secret-key")
+// AuthRequired hata kodları; istemciler "code" alanına göre davranır
+// (ör. token_expired gelince refresh dener, diğerlerinde yeniden login).
+const (
+	ErrCodeTokenMissing   = "token_missing"
+	ErrCodeTokenMalformed = "token_malformed"
+	ErrCodeTokenExpired   = "token_expired"
+	ErrCodeTokenInvalid   = "token_invalid"
+	ErrCodeTokenRevoked   = "token_revoked"
+)
 
-func AuthRequired() gin.HandlerFunc {
+// AuthRequired Bearer JWT ister. APIKeyAuth isteği zaten doğruladıysa
+// JWT kontrolü atlanır.
+func AuthRequired(tokenService *service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if c.GetString("auth_method") == AuthMethodAPIKey {
+			c.Next()
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+			abortUnauthorized(c, ErrCodeTokenMissing, "Authorization header required")
 			return
 		}
 
 		// "Bearer <token>" formatı
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Authorization header"})
-			c.Abort()
+		if tokenString == authHeader || tokenString == "" {
+			abortUnauthorized(c, ErrCodeTokenMalformed, "Invalid Authorization header")
 			return
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return JwtSecret, nil
-		})
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		claims, err := tokenService.ParseAccessToken(tokenString)
+		if errors.Is(err, service.ErrAccessTokenExpired) {
+			abortUnauthorized(c, ErrCodeTokenExpired, "Token has expired")
+			return
+		}
+		if err != nil {
+			abortUnauthorized(c, ErrCodeTokenInvalid, "Invalid token")
+			return
+		}
+
+		userID := claims.UserID
+		issuedAt := claims.IssuedAt.Time
+		revoked, err := tokenService.IsAccessTokenRevoked(claims.ID, claims.SessionID, userID, issuedAt)
+		// Impersonation token'ı, admin'in kendi token'ları iptal edildiğinde de düşer.
+		var actorID string
+		if claims.Actor != nil {
+			actorID = claims.Actor.Subject
+		}
+		if err == nil && !revoked && actorID != "" {
+			revoked, err = tokenService.IsAccessTokenRevoked("", "", actorID, issuedAt)
+		}
+		// OAuth token'ları client silindiğinde veya kullanıcı onayı geri aldığında düşer.
+		if err == nil && !revoked && claims.ClientID != "" {
+			revoked, err = tokenService.IsOAuthGrantRevoked(claims.ClientID, userID, issuedAt)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not verify token"})
 			c.Abort()
 			return
 		}
+		if revoked {
+			abortUnauthorized(c, ErrCodeTokenRevoked, "Token has been revoked")
+			return
+		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		permissions, err := tokenService.PermissionsFor(claims.Roles)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not resolve permissions"})
 			c.Abort()
 			return
 		}
 
-		if uid, ok := claims["user_id"].(string); ok {
-			c.Set("user_id", uid)
-		} else {
-			c.Set("user_id", fmt.Sprintf("%v", claims["user_id"]))
+		tokenService.TouchSession(claims.SessionID, c.ClientIP())
+
+		authMethod := AuthMethodJWT
+		if claims.ClientID != "" {
+			// OAuth token'ında izinler scope'larla sınırlıdır. Kullanıcı adına
+			// alınmışsa kullanıcının güncel izinleriyle de kesişir; client
+			// credentials token'ında kullanıcı yoktur.
+			scopes := claims.Scopes()
+			if userID == "" {
+				permissions = scopes
+			} else {
+				permissions = slices.DeleteFunc(permissions, func(p string) bool { return !slices.Contains(scopes, p) })
+			}
+			authMethod = AuthMethodOAuth
+			c.Set("client_id", claims.ClientID)
+			c.Set("scopes", scopes)
+		}
+
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+		c.Set("session_id", claims.SessionID)
+		c.Set("roles", claims.Roles)
+		c.Set("permissions", permissions)
+		c.Set("claims", claims)
+		if actorID != "" {
+			c.Set("actor_id", actorID)
 		}
+		c.Set("auth_method", authMethod)
+
+		p := &principal.Principal{
+			Kind:           principal.KindUser,
+			UserID:         userID,
+			SessionID:      claims.SessionID,
+			ClientID:       claims.ClientID,
+			ActorID:        actorID,
+			Roles:          claims.Roles,
+			Permissions:    permissions,
+			Scopes:         claims.Scopes(),
+			OrganizationID: claims.OrganizationID,
+		}
+		if userID == "" {
+			p.Kind = principal.KindClient
+		}
+		p.SetUserLoader(tokenService.LoadUser)
+		setPrincipal(c, p)
 		c.Next()
 	}
 }
+
+// abortUnauthorized 401 döner; WWW-Authenticate RFC 6750'deki gibi doldurulur.
+func abortUnauthorized(c *gin.Context, code, message string) {
+	if code == ErrCodeTokenMissing {
+		c.Header("WWW-Authenticate", `Bearer`)
+	} else {
+		c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, message))
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": message, "code": code})
+	c.Abort()
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"encoding/json"
+	"go-initial-project/config"
+	"go-initial-project/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// accessClaims geçerli bir access token'ın claim'leri; testler tek bir alanı bozar.
+func accessClaims() *service.AccessClaims {
+	userID := uuid.New().String()
+	now := time.Now()
+	return &service.AccessClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    config.AppConfig.JWT.Issuer,
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{config.AppConfig.JWT.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+// signToken claim'leri verilen yöntemle ve test anahtarının kid'i ile imzalar.
+func signToken(t *testing.T, method jwt.SigningMethod, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = testKeyID
+	var key interface{} = []byte(testSecret)
+	if method == jwt.SigningMethodNone {
+		key = jwt.UnsafeAllowNoneSignatureType
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// authRequest AuthRequired arkasındaki bir uca istek atar.
+func authRequest(t *testing.T, tokenService *service.TokenService, authorization string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := gin.New()
+	r.GET("/me", AuthRequired(tokenService), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id")})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	return body.Code
+}
+
+func TestAuthRequiredRejectsBadTokens(t *testing.T) {
+	tokenService := newTokenService(t)
+	leeway := config.AppConfig.JWT.Leeway
+
+	wrongIssuer := accessClaims()
+	wrongIssuer.Issuer = "someone-else"
+	wrongAudience := accessClaims()
+	wrongAudience.Audience = jwt.ClaimStrings{"another-api"}
+	expired := accessClaims()
+	expired.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-2 * leeway))
+	noExpiry := accessClaims()
+	noExpiry.ExpiresAt = nil
+	noJTI := accessClaims()
+	noJTI.ID = ""
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims())
+	forged.Header["kid"] = testKeyID
+	forgedToken, err := forged.SignedString([]byte("not-the-real-secret-0123456789"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		code          string
+	}{
+		{"missing header", "", ErrCodeTokenMissing},
+		{"wrong scheme", "Basic dXNlcjpwYXNz", ErrCodeTokenMalformed},
+		{"empty bearer", "Bearer ", ErrCodeTokenMalformed},
+		{"garbage", "Bearer not-a-jwt", ErrCodeTokenInvalid},
+		{"alg none", "Bearer " + signToken(t, jwt.SigningMethodNone, accessClaims()), ErrCodeTokenInvalid},
+		{"wrong algorithm", "Bearer " + signToken(t, jwt.SigningMethodHS512, accessClaims()), ErrCodeTokenInvalid},
+		{"wrong secret", "Bearer " + forgedToken, ErrCodeTokenInvalid},
+		{"wrong issuer", "Bearer " + signToken(t, jwt.SigningMethodHS256, wrongIssuer), ErrCodeTokenInvalid},
+		{"wrong audience", "Bearer " + signToken(t, jwt.SigningMethodHS256, wrongAudience), ErrCodeTokenInvalid},
+		{"missing exp", "Bearer " + signToken(t, jwt.SigningMethodHS256, noExpiry), ErrCodeTokenInvalid},
+		{"missing jti", "Bearer " + signToken(t, jwt.SigningMethodHS256, noJTI), ErrCodeTokenInvalid},
+		{"expired past leeway", "Bearer " + signToken(t, jwt.SigningMethodHS256, expired), ErrCodeTokenExpired},
+	}
+	for _, tt := range tests {
+		w := authRequest(t, tokenService, tt.authorization)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", tt.name, w.Code)
+			continue
+		}
+		if code := errorCode(t, w); code != tt.code {
+			t.Errorf("%s: code = %q, want %q", tt.name, code, tt.code)
+		}
+		challenge := w.Header().Get("WWW-Authenticate")
+		if !strings.HasPrefix(challenge, "Bearer") {
+			t.Errorf("%s: WWW-Authenticate = %q", tt.name, challenge)
+		}
+		if tt.code != ErrCodeTokenMissing && !strings.Contains(challenge, `error="invalid_token"`) {
+			t.Errorf("%s: WWW-Authenticate = %q, want invalid_token", tt.name, challenge)
+		}
+	}
+}
+
+func TestAuthRequiredAcceptsExpiredWithinLeeway(t *testing.T) {
+	tokenService := newTokenService(t)
+	claims := accessClaims()
+	claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-config.AppConfig.JWT.Leeway / 2))
+
+	w := authRequest(t, tokenService, "Bearer "+signToken(t, jwt.SigningMethodHS256, claims))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), claims.UserID) {
+		t.Fatalf("body = %s, want user %s", w.Body.String(), claims.UserID)
+	}
+}
+
+func TestAuthRequiredRejectsRevokedToken(t *testing.T) {
+	tokenService := newTokenService(t)
+	claims := accessClaims()
+	token := "Bearer " + signToken(t, jwt.SigningMethodHS256, claims)
+
+	if w := authRequest(t, tokenService, token); w.Code != http.StatusOK {
+		t.Fatalf("status = %d before revocation", w.Code)
+	}
+	if err := tokenService.RevokeAccessToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+		t.Fatal(err)
+	}
+	w := authRequest(t, tokenService, token)
+	if w.Code != http.StatusUnauthorized || errorCode(t, w) != ErrCodeTokenRevoked {
+		t.Fatalf("status = %d, body = %s; want 401 token_revoked", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission AuthRequired'dan sonra kullanılır; istenen tüm izinler
+// yoksa 403 döner.
+//
+//	users.DELETE("/:id", middleware.RequirePermission("users:delete"), uc.Delete)
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := c.GetStringSlice("permissions")
+		for _, perm := range permissions {
+			if !slices.Contains(granted, perm) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "missing permission: " + perm})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"go-initial-project/principal"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrincipalKey doğrulanmış principal'ın gin context'indeki anahtarı.
+const PrincipalKey = "principal"
+
+// setPrincipal principal'ı gin context'ine ve request'in context.Context'ine
+// koyar; servisler principal.FromContext ile okuyabilir.
+func setPrincipal(c *gin.Context, p *principal.Principal) {
+	c.Set(PrincipalKey, p)
+	c.Request = c.Request.WithContext(principal.NewContext(c.Request.Context(), p))
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/keyring"
+	"go-initial-project/repository"
+	"go-initial-project/service"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	testKeyID  = "test"
+	testSecret = "middleware-test-secret-0123456789"
+)
+
+func TestMain(m *testing.M) {
+	config.LoadEnv()
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// newTokenService SQLite üzerinde, testKeyID ile HS256 imzalayan bir TokenService kurar.
+func newTokenService(t *testing.T) *service.TokenService {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(
+		&entity.User{},
+		&entity.Permission{},
+		&entity.Role{},
+		&entity.RefreshToken{},
+		&entity.Session{},
+		&entity.APIKey{},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := keyring.New()
+	key, err := keyring.NewHMACKey(testKeyID, []byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Add(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.SetActive(testKeyID); err != nil {
+		t.Fatal(err)
+	}
+
+	roleRepo := repository.NewRoleRepository(db)
+	refreshTokens := repository.NewRefreshTokenRepository(db)
+	revocations := service.NewMemoryRevocationStore(time.Hour)
+	users := service.NewUserService(repository.NewUserRepository(db), roleRepo, nil, nil)
+	roles := service.NewRoleService(roleRepo)
+	sessions := service.NewSessionService(repository.NewSessionRepository(db), refreshTokens, revocations)
+	if err := roles.SeedDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	return service.NewTokenService(keys, users, roles, refreshTokens, repository.NewAPIKeyRepository(db), revocations, sessions)
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/principal"
+	"go-initial-project/service"
+	"go-initial-project/tenant"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TenantHeader oturumda organizasyon seçilmemişse (API anahtarları, OAuth
+// token'ları) aktif organizasyon bu header'dan okunur.
+const TenantHeader = "X-Organization-ID"
+
+// ResolveTenant AuthRequired'dan sonra kullanılır. Aktif organizasyonu
+// token'ın "org" claim'inden ya da X-Organization-ID header'ından çözer,
+// kullanıcının üyeliğini doğrular ve tenant'ı request'in context'ine koyar;
+// sonraki tüm sorgular bu organizasyonla sınırlıdır.
+func ResolveTenant(organizationService *service.OrganizationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get(PrincipalKey)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+		p := value.(*principal.Principal)
+
+		// Claim'deki id token üretilirken kanonik biçimdedir.
+		organizationID := p.OrganizationID
+		if header := c.GetHeader(TenantHeader); header != "" {
+			id, err := uuid.Parse(header)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization id"})
+				c.Abort()
+				return
+			}
+			if organizationID != "" && id.String() != organizationID {
+				c.JSON(http.StatusForbidden, gin.H{"error": "token is bound to another organization"})
+				c.Abort()
+				return
+			}
+			organizationID = id.String()
+		}
+		if organizationID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "no organization selected, switch to one or send the " + TenantHeader + " header"})
+			c.Abort()
+			return
+		}
+		if p.UserID == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": service.ErrNotMember.Error()})
+			c.Abort()
+			return
+		}
+
+		ctx := tenant.NewContext(c.Request.Context(), organizationID)
+		membership, err := organizationService.Membership(ctx, p.UserID)
+		if errors.Is(err, service.ErrNotMember) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not resolve organization"})
+			c.Abort()
+			return
+		}
+
+		p.OrganizationID = organizationID
+		p.OrgRole = membership.Role
+		c.Set("organization_id", organizationID)
+		c.Set("org_role", membership.Role)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequireOrgPermission ResolveTenant'tan sonra kullanılır; üyenin
+// organizasyondaki rolü istenen tüm izinleri vermiyorsa 403 döner.
+//
+//	current.DELETE("", middleware.RequireOrgPermission(entity.OrgPermDelete), oc.Delete)
+func RequireOrgPermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := entity.OrgPermissionsFor(c.GetString("org_role"))
+		for _, perm := range permissions {
+			if !slices.Contains(granted, perm) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "missing organization permission: " + perm})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
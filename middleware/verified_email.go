@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"go-initial-project/principal"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail AuthRequired'dan sonra kullanılır; e-postasını
+// doğrulamamış kullanıcıları 403 ile durdurur. Kullanıcı principal üzerinden
+// yüklendiği için token'daki eski email_verified claim'ine ve API anahtarı ile
+// yapılan isteklere bakılmaksızın güncel durum kontrol edilir.
+//
+//	keys.POST("", middleware.RequireVerifiedEmail(), kc.Create)
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get(PrincipalKey)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+		user, err := value.(*principal.Principal).User(c.Request.Context())
+		if err != nil || !user.EmailVerified() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "email not verified"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package passwordpolicy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Breached sızdırılmış şifre listesine karşı kontrol. Ağa çıkmayan yerel
+// kaynaklar için tasarlanmıştır (bkz. SHA1File).
+type Breached interface {
+	Contains(password string) (bool, error)
+}
+
+// Policy yeni şifrelerin uyması gereken kurallar.
+type Policy struct {
+	MinLength     int
+	MaxLength     int // bcrypt 72 byte'tan uzun şifreleri kabul etmez
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// RejectPersonal şifrenin e-posta veya isim içermesini engeller.
+	RejectPersonal bool
+	Breached       Breached
+}
+
+// Error bir şifrenin ihlal ettiği kuralların listesi. Controller'lar bunu
+// alan bazlı hata olarak döner.
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "password does not meet the policy: " + strings.Join(e.Problems, ", ")
+}
+
+// Check şifreyi kurallara göre kontrol eder. personal kullanıcının e-postası
+// ve isimleri gibi şifrede geçmemesi gereken değerlerdir. Kural ihlali
+// varsa *Error döner; diğer hatalar sızıntı kontrolünden gelir.
+func (p *Policy) Check(password string, personal ...string) error {
+	var problems []string
+
+	length := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && length < p.MinLength {
+		problems = append(problems, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		problems = append(problems, fmt.Sprintf("must be at most %d bytes", p.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r), unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		problems = append(problems, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		problems = append(problems, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		problems = append(problems, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		problems = append(problems, "must contain a symbol")
+	}
+
+	if p.RejectPersonal && containsPersonal(password, personal) {
+		problems = append(problems, "must not contain your e-mail or name")
+	}
+
+	if p.Breached != nil {
+		breached, err := p.Breached.Contains(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			problems = append(problems, "has appeared in a data breach, choose a different one")
+		}
+	}
+
+	if len(problems) > 0 {
+		return &Error{Problems: problems}
+	}
+	return nil
+}
+
+// containsPersonal e-postanın yerel kısmı ve isimler için büyük/küçük harf
+// duyarsız arama yapar. Çok kısa değerler (ör. "Al") yanlış pozitif
+// vermemesi için atlanır.
+func containsPersonal(password string, personal []string) bool {
+	lowered := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if local, _, ok := strings.Cut(value, "@"); ok {
+			value = local
+		}
+		if utf8.RuneCountInString(value) < 3 {
+			continue
+		}
+		if strings.Contains(lowered, value) {
+			return true
+		}
+	}
+	return false
+}
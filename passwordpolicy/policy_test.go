@@ -0,0 +1,115 @@
+package passwordpolicy
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCheckRules(t *testing.T) {
+	policy := &Policy{
+		MinLength:     8,
+		MaxLength:     16,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		password string
+		problem  string
+	}{
+		{"Sh0rt!", "must be at least 8 characters"},
+		{"Much-T00-Long-Password", "must be at most 16 bytes"},
+		// 13 karakter ama 20 byte: üst sınır byte cinsindendir.
+		{"Şifreğüçöİı-1", "must be at most 16 bytes"},
+		{"no-upper-1", "must contain an uppercase letter"},
+		{"NO-LOWER-1", "must contain a lowercase letter"},
+		{"No-Digits-Here", "must contain a digit"},
+		{"NoSymbols123", "must contain a symbol"},
+	}
+	for _, tt := range tests {
+		err := policy.Check(tt.password)
+		var policyErr *Error
+		if !errors.As(err, &policyErr) {
+			t.Errorf("Check(%q) = %v, want *Error", tt.password, err)
+			continue
+		}
+		if !slices.Contains(policyErr.Problems, tt.problem) {
+			t.Errorf("Check(%q) problems = %v, want %q", tt.password, policyErr.Problems, tt.problem)
+		}
+	}
+
+	for _, password := range []string{"Valid-Pass1", "Boşluk ile 1A", "Uni©ode-Pass9"} {
+		if err := policy.Check(password); err != nil {
+			t.Errorf("Check(%q) = %v, want nil", password, err)
+		}
+	}
+}
+
+func TestCheckReportsEveryProblem(t *testing.T) {
+	policy := &Policy{MinLength: 8, RequireUpper: true, RequireDigit: true, RequireSymbol: true}
+
+	var policyErr *Error
+	if !errors.As(policy.Check("abc"), &policyErr) {
+		t.Fatal("want *Error")
+	}
+	if len(policyErr.Problems) != 4 {
+		t.Fatalf("problems = %v, want 4", policyErr.Problems)
+	}
+}
+
+func TestCheckPersonal(t *testing.T) {
+	policy := &Policy{RejectPersonal: true}
+	personal := []string{"Jane.Doe@example.com", "Jane", "Al"}
+
+	tests := []struct {
+		password string
+		rejected bool
+	}{
+		{"my-jane.doe-password", true},
+		{"JANE.DOE!2024", true},
+		{"i-am-JANE", true},
+		// E-postanın sadece yerel kısmı aranır.
+		{"example.com-rocks", false},
+		// 3 karakterden kısa değerler atlanır.
+		{"always-Al", false},
+		{"unrelated-password", false},
+	}
+	for _, tt := range tests {
+		err := policy.Check(tt.password, personal...)
+		if rejected := err != nil; rejected != tt.rejected {
+			t.Errorf("Check(%q) = %v, want rejected = %v", tt.password, err, tt.rejected)
+		}
+	}
+
+	if err := (&Policy{}).Check("jane.doe", personal...); err != nil {
+		t.Fatalf("RejectPersonal disabled: %v", err)
+	}
+}
+
+type breachedFunc func(string) (bool, error)
+
+func (f breachedFunc) Contains(password string) (bool, error) { return f(password) }
+
+func TestCheckBreached(t *testing.T) {
+	policy := &Policy{Breached: breachedFunc(func(password string) (bool, error) {
+		return password == "password123", nil
+	})}
+
+	var policyErr *Error
+	if !errors.As(policy.Check("password123"), &policyErr) {
+		t.Fatal("breached password accepted")
+	}
+	if err := policy.Check("correct horse battery staple"); err != nil {
+		t.Fatalf("Check = %v, want nil", err)
+	}
+
+	// Sızıntı kontrolü hata verirse kural ihlali değil hata döner.
+	lookupErr := errors.New("disk error")
+	policy.Breached = breachedFunc(func(string) (bool, error) { return false, lookupErr })
+	if err := policy.Check("anything"); !errors.Is(err, lookupErr) || errors.As(err, &policyErr) {
+		t.Fatalf("err = %v, want the lookup error", err)
+	}
+}
@@ -0,0 +1,135 @@
+package passwordpolicy
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var ErrInvalidSHA1File = errors.New("breached password file must list SHA-1 hashes, one per line")
+
+// SHA1File SHA-1 hash'lerine göre sıralanmış bir sızıntı listesi, ör.
+// "Have I Been Pwned" ordered-by-hash indirmesi. Her satır büyük harfli
+// 40 karakterlik hex hash ile başlar, ardından ":<sayı>" gelebilir.
+// Dosya belleğe yüklenmez; her kontrol disk üzerinde binary search yapar.
+type SHA1File struct {
+	file *os.File
+	size int64
+}
+
+func OpenSHA1File(path string) (*SHA1File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	list := &SHA1File{file: f, size: info.Size()}
+	if err := list.checkFormat(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list, nil
+}
+
+// checkFormat ilk satırın hex hash olduğunu kontrol eder. Düz metin bir
+// şifre listesi hata vermeden hiçbir şifreyle eşleşmezdi.
+func (s *SHA1File) checkFormat() error {
+	line, err := s.readLine(0)
+	if err != nil {
+		return err
+	}
+	hash, _, _ := bytes.Cut(line, []byte(":"))
+	if len(hash) != sha1.Size*2 {
+		return ErrInvalidSHA1File
+	}
+	if _, err := hex.DecodeString(string(hash)); err != nil {
+		return ErrInvalidSHA1File
+	}
+	return nil
+}
+
+func (s *SHA1File) Close() error {
+	return s.file.Close()
+}
+
+func (s *SHA1File) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	target := []byte(strings.ToUpper(hex.EncodeToString(sum[:])))
+
+	// Değişmez: eşleşen satır varsa başlangıcı [lo, hi) aralığındadır.
+	lo, hi := int64(0), s.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, err := s.lineStart(mid)
+		if err != nil {
+			return false, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+		line, err := s.readLine(start)
+		if err != nil {
+			return false, err
+		}
+		hash := line
+		if len(hash) > len(target) {
+			hash = hash[:len(target)]
+		}
+		switch cmp := bytes.Compare(bytes.ToUpper(hash), target); {
+		case cmp == 0:
+			return true, nil
+		case cmp < 0:
+			lo = start + int64(len(line)) + 1
+		default:
+			hi = mid
+		}
+	}
+	return false, nil
+}
+
+// lineStart offset'te veya sonrasında başlayan ilk satırın başını bulur.
+func (s *SHA1File) lineStart(offset int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+	buf := make([]byte, 128)
+	pos := offset - 1
+	for pos < s.size {
+		n, err := s.file.ReadAt(buf, pos)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return pos + int64(i) + 1, nil
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		pos += int64(n)
+	}
+	return s.size, nil
+}
+
+// readLine satır sonu hariç satırı döner; \r\n ile biten dosyalar da okunur.
+func (s *SHA1File) readLine(start int64) ([]byte, error) {
+	buf := make([]byte, 128)
+	n, err := s.file.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	line := buf[:n]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	return bytes.TrimRight(line, "\r"), nil
+}
@@ -0,0 +1,94 @@
+package passwordpolicy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeSHA1File parolaların sıralı SHA-1 listesini HIBP formatında yazar.
+func writeSHA1File(t *testing.T, newline string, passwords ...string) string {
+	t.Helper()
+	lines := make([]string, len(passwords))
+	for i, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		lines[i] = strings.ToUpper(hex.EncodeToString(sum[:])) + ":" + string(rune('1'+i%9))
+	}
+	sort.Strings(lines)
+	return writeFile(t, strings.Join(lines, newline)+newline)
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func openSHA1File(t *testing.T, path string) *SHA1File {
+	t.Helper()
+	list, err := OpenSHA1File(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { list.Close() })
+	return list
+}
+
+func TestSHA1FileContains(t *testing.T) {
+	breached := []string{"password", "123456", "qwerty", "letmein", "dragon", "monkey", "111111", "iloveyou"}
+
+	for name, newline := range map[string]string{"LF": "\n", "CRLF": "\r\n"} {
+		list := openSHA1File(t, writeSHA1File(t, newline, breached...))
+		for _, password := range breached {
+			if ok, err := list.Contains(password); err != nil || !ok {
+				t.Errorf("%s: Contains(%q) = %v, %v; want true", name, password, ok, err)
+			}
+		}
+		for _, password := range []string{"", "Password", "correct horse battery staple"} {
+			if ok, err := list.Contains(password); err != nil || ok {
+				t.Errorf("%s: Contains(%q) = %v, %v; want false", name, password, ok, err)
+			}
+		}
+	}
+}
+
+func TestSHA1FileAcceptsLowercaseHashes(t *testing.T) {
+	sum := sha1.Sum([]byte("password"))
+	list := openSHA1File(t, writeFile(t, hex.EncodeToString(sum[:])+"\n"))
+
+	if ok, err := list.Contains("password"); err != nil || !ok {
+		t.Fatalf("Contains = %v, %v; want true", ok, err)
+	}
+}
+
+func TestOpenSHA1FileMissing(t *testing.T) {
+	_, err := OpenSHA1File(filepath.Join(t.TempDir(), "missing.txt"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("err = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestOpenSHA1FileMalformed(t *testing.T) {
+	tests := map[string]string{
+		"empty":              "",
+		"plaintext list":     "password\n123456\n",
+		"short hash":         "5BAA61E4C9B93F3F0682250B6CF8331B7EE68F:3\n",
+		"not hex":            "ZBAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:3\n",
+		"ntlm instead":       "8846F7EAEE8FB117AD06BDD830B7586C:3\n",
+		"leading whitespace": " 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8\n",
+	}
+	for name, content := range tests {
+		if _, err := OpenSHA1File(writeFile(t, content)); !errors.Is(err, ErrInvalidSHA1File) {
+			t.Errorf("%s: err = %v, want ErrInvalidSHA1File", name, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package principal
+
+import (
+	"context"
+	"errors"
+	"go-initial-project/entity"
+	"sync"
+)
+
+// ErrNoUser principal bir kullanıcıya ait değilse (client credentials) döner.
+var ErrNoUser = errors.New("principal has no user")
+
+// Kind isteği kimin yaptığını söyler.
+type Kind string
+
+const (
+	// KindUser kullanıcının kendi oturumu veya kullanıcı adına alınmış OAuth token'ı.
+	KindUser Kind = "user"
+	// KindAPIKey kullanıcının oluşturduğu API anahtarı.
+	KindAPIKey Kind = "api_key"
+	// KindClient client credentials ile token almış OAuth client'ı; kullanıcı yoktur.
+	KindClient Kind = "client"
+)
+
+// UserLoader principal'ın kullanıcısını yükler.
+type UserLoader func(ctx context.Context, userID string) (*entity.User, error)
+
+// Principal doğrulanmış isteğin sahibi. Auth middleware'leri tarafından
+// oluşturulur; gin context'ine ve request'in context.Context'ine konur.
+type Principal struct {
+	Kind      Kind
+	UserID    string
+	SessionID string
+	APIKeyID  string
+	ClientID  string
+	// ActorID impersonation sırasında işlemi gerçekte yapan admin.
+	ActorID     string
+	Roles       []string
+	Permissions []string
+	Scopes      []string
+	// OrganizationID token'ın bağlı olduğu ("org" claim'i) ya da
+	// ResolveTenant'ın header'dan çözdüğü organizasyon. OrgRole sadece
+	// ResolveTenant üyeliği doğruladıktan sonra dolar.
+	OrganizationID string
+	OrgRole        string
+
+	load UserLoader
+	once sync.Once
+	user *entity.User
+	err  error
+}
+
+// SetUserLoader User'ın kullanacağı yükleyiciyi ayarlar.
+func (p *Principal) SetUserLoader(load UserLoader) {
+	p.load = load
+}
+
+// SetUser kullanıcı zaten yüklendiyse (ör. API anahtarı doğrulanırken)
+// tekrar sorgulanmaması için önbelleğe koyar.
+func (p *Principal) SetUser(user *entity.User) {
+	p.once.Do(func() { p.user = user })
+}
+
+// User principal'ın kullanıcısını döner. İstek başına bir kez yüklenir;
+// sonraki çağrılar aynı sonucu döner.
+func (p *Principal) User(ctx context.Context) (*entity.User, error) {
+	p.once.Do(func() {
+		if p.UserID == "" || p.load == nil {
+			p.err = ErrNoUser
+			return
+		}
+		p.user, p.err = p.load(ctx, p.UserID)
+	})
+	return p.user, p.err
+}
+
+// Impersonated isteğin bir admin tarafından kullanıcı adına yapıldığını söyler.
+func (p *Principal) Impersonated() bool {
+	return p.ActorID != ""
+}
+
+type contextKey struct{}
+
+// NewContext principal'ı ctx'e ekler.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext servis ve repository'lerin işlemi kimin yaptığını görmesi için.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	*BaseRepository[entity.APIKey, string]
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		BaseRepository: NewBaseRepository[entity.APIKey, string](db),
+	}
+}
+
+// FindByPrefix bulunamazsa gorm'un "record not found" logunu basmamak için
+// First yerine Find kullanır; her istekte çağrılır.
+func (r *APIKeyRepository) FindByPrefix(prefix string) (*entity.APIKey, error) {
+	var keys []entity.APIKey
+	if err := r.db.Where("prefix = ?", prefix).Limit(1).Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &keys[0], nil
+}
+
+func (r *APIKeyRepository) ListForUser(userID string) ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Revoke kullanıcının aktif anahtarını iptal eder; anahtar başkasına aitse
+// veya zaten iptal edilmişse false döner.
+func (r *APIKeyRepository) Revoke(userID, id string, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RevokeAllForUser kullanıcının tüm aktif anahtarlarını iptal eder.
+func (r *APIKeyRepository) RevokeAllForUser(userID string, at time.Time) error {
+	return r.db.Model(&entity.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func (r *APIKeyRepository) Touch(id string, at time.Time) error {
+	return r.db.Model(&entity.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
@@ -8,76 +8,76 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-type BaseRepository[T any] struct {
+type BaseRepository[T any, ID comparable] struct {
 	db *gorm.DB
 }
 
-func NewBaseRepository[T any](db *gorm.DB) *BaseRepository[T] {
-	return &BaseRepository[T]{db}
+func NewBaseRepository[T any, ID comparable](db *gorm.DB) *BaseRepository[T, ID] {
+	return &BaseRepository[T, ID]{db}
 }
 
 // ---------------- BASIC CRUD ----------------
 
-func (r *BaseRepository[T]) FindAll() ([]T, error) {
+func (r *BaseRepository[T, ID]) FindAll() ([]T, error) {
 	var items []T
 	err := r.db.Find(&items).Error
 	return items, err
 }
 
-func (r *BaseRepository[T]) FindByID(id uint) (T, error) {
+func (r *BaseRepository[T, ID]) FindByID(id ID) (T, error) {
 	var item T
-	err := r.db.First(&item, id).Error
+	err := r.db.Where(byID(id)).First(&item).Error
 	return item, err
 }
 
-func (r *BaseRepository[T]) Create(item *T) error {
+func (r *BaseRepository[T, ID]) Create(item *T) error {
 	return r.db.Create(item).Error
 }
 
-func (r *BaseRepository[T]) Update(item T) (T, error) {
+func (r *BaseRepository[T, ID]) Update(item T) (T, error) {
 	err := r.db.Save(&item).Error
 	return item, err
 }
 
-func (r *BaseRepository[T]) Delete(id uint, item T) error {
-	return r.db.Delete(&item, id).Error
+func (r *BaseRepository[T, ID]) Delete(id ID, item T) error {
+	return r.db.Where(byID(id)).Delete(&item).Error
 }
 
-func (r *BaseRepository[T]) HardDelete(id uint, item T) error {
-	return r.db.Unscoped().Delete(&item, id).Error
+func (r *BaseRepository[T, ID]) HardDelete(id ID, item T) error {
+	return r.db.Unscoped().Where(byID(id)).Delete(&item).Error
 }
 
 // ---------------- BULK OPS ----------------
 
-func (r *BaseRepository[T]) UpdateWhere(where map[string]interface{}, values map[string]interface{}) error {
+func (r *BaseRepository[T, ID]) UpdateWhere(where map[string]interface{}, values map[string]interface{}) error {
 	var item T
 	return r.db.Model(&item).Where(where).Updates(values).Error
 }
 
-func (r *BaseRepository[T]) DeleteWhere(where map[string]interface{}) error {
+func (r *BaseRepository[T, ID]) DeleteWhere(where map[string]interface{}) error {
 	var item T
 	return r.db.Where(where).Delete(&item).Error
 }
 
-func (r *BaseRepository[T]) CreateBatch(items []T, batchSize int) error {
+func (r *BaseRepository[T, ID]) CreateBatch(items []T, batchSize int) error {
 	return r.db.CreateInBatches(items, batchSize).Error
 }
 
 // ---------------- FIND / FILTER ----------------
 
-func (r *BaseRepository[T]) First(where map[string]interface{}) (T, error) {
+func (r *BaseRepository[T, ID]) First(where map[string]interface{}) (T, error) {
 	var item T
 	err := r.db.Where(where).First(&item).Error
 	return item, err
 }
 
-func (r *BaseRepository[T]) Where(where map[string]interface{}) ([]T, error) {
+func (r *BaseRepository[T, ID]) Where(where map[string]interface{}) ([]T, error) {
 	var items []T
 	err := r.db.Where(where).Find(&items).Error
 	return items, err
 }
 
-func (r *BaseRepository[T]) Filter(where map[string]interface{}) ([]T, error) {
+func (r *BaseRepository[T, ID]) Filter(where map[string]interface{}) ([]T, error) {
 	var items []T
 	query := r.db
 	for key, val := range where {
@@ -87,19 +87,19 @@ func (r *BaseRepository[T]) Filter(where map[string]interface{}) ([]T, error) {
 	return items, err
 }
 
-func (r *BaseRepository[T]) Between(field string, from, to interface{}) ([]T, error) {
+func (r *BaseRepository[T, ID]) Between(field string, from, to interface{}) ([]T, error) {
 	var items []T
 	err := r.db.Where(fmt.Sprintf("%s BETWEEN ? AND ?", field), from, to).Find(&items).Error
 	return items, err
 }
 
-func (r *BaseRepository[T]) In(field string, values []interface{}) ([]T, error) {
+func (r *BaseRepository[T, ID]) In(field string, values []interface{}) ([]T, error) {
 	var items []T
 	err := r.db.Where(fmt.Sprintf("%s IN ?", field), values).Find(&items).Error
 	return items, err
 }
 
-func (r *BaseRepository[T]) NotIn(field string, values []interface{}) ([]T, error) {
+func (r *BaseRepository[T, ID]) NotIn(field string, values []interface{}) ([]T, error) {
 	var items []T
 	err := r.db.Where(fmt.Sprintf("%s NOT IN ?", field), values).Find(&items).Error
 	return items, err
@@ -107,42 +107,42 @@ func (r *BaseRepository[T]) NotIn(field string, values []interface{}) ([]T, erro
 
 // ---------------- AGGREGATES ----------------
 
-func (r *BaseRepository[T]) Count() (int64, error) {
+func (r *BaseRepository[T, ID]) Count() (int64, error) {
 	var count int64
 	var item T
 	err := r.db.Model(&item).Count(&count).Error
 	return count, err
 }
 
-func (r *BaseRepository[T]) Sum(field string) (float64, error) {
+func (r *BaseRepository[T, ID]) Sum(field string) (float64, error) {
 	var result float64
 	var item T
 	err := r.db.Model(&item).Select("SUM(" + field + ")").Scan(&result).Error
 	return result, err
 }
 
-func (r *BaseRepository[T]) Avg(field string) (float64, error) {
+func (r *BaseRepository[T, ID]) Avg(field string) (float64, error) {
 	var result float64
 	var item T
 	err := r.db.Model(&item).Select("AVG(" + field + ")").Scan(&result).Error
 	return result, err
 }
 
-func (r *BaseRepository[T]) Min(field string) (float64, error) {
+func (r *BaseRepository[T, ID]) Min(field string) (float64, error) {
 	var result float64
 	var item T
 	err := r.db.Model(&item).Select("MIN(" + field + ")").Scan(&result).Error
 	return result, err
 }
 
-func (r *BaseRepository[T]) Max(field string) (float64, error) {
+func (r *BaseRepository[T, ID]) Max(field string) (float64, error) {
 	var result float64
 	var item T
 	err := r.db.Model(&item).Select("MAX(" + field + ")").Scan(&result).Error
 	return result, err
 }
 
-func (r *BaseRepository[T]) GroupBy(field string) ([]map[string]interface{}, error) {
+func (r *BaseRepository[T, ID]) GroupBy(field string) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 	var item T
 	err := r.db.Model(&item).Select(field + ", COUNT(*) as count").Group(field).Scan(&results).Error
@@ -151,13 +151,13 @@ func (r *BaseRepository[T]) GroupBy(field string) ([]map[string]interface{}, err
 
 // ---------------- ORDER / PAGINATION ----------------
 
-func (r *BaseRepository[T]) OrderBy(order string) ([]T, error) {
+func (r *BaseRepository[T, ID]) OrderBy(order string) ([]T, error) {
 	var items []T
 	err := r.db.Order(order).Find(&items).Error
 	return items, err
 }
 
-func (r *BaseRepository[T]) OrderByMultiple(orders []string) ([]T, error) {
+func (r *BaseRepository[T, ID]) OrderByMultiple(orders []string) ([]T, error) {
 	var items []T
 	query := r.db
 	for _, order := range orders {
@@ -167,7 +167,7 @@ func (r *BaseRepository[T]) OrderByMultiple(orders []string) ([]T, error) {
 	return items, err
 }
 
-func (r *BaseRepository[T]) Paginate(offset int, limit int) ([]T, int64, error) {
+func (r *BaseRepository[T, ID]) Paginate(offset int, limit int) ([]T, int64, error) {
 	var items []T
 	var count int64
 	var item T
@@ -179,7 +179,7 @@ func (r *BaseRepository[T]) Paginate(offset int, limit int) ([]T, int64, error)
 
 // ---------------- SEARCH ----------------
 
-func (r *BaseRepository[T]) Search(field, keyword string) ([]T, error) {
+func (r *BaseRepository[T, ID]) Search(field, keyword string) ([]T, error) {
 	var items []T
 	err := r.db.Where(field+" LIKE ?", "%"+keyword+"%").Find(&items).Error
 	return items, err
@@ -187,33 +187,33 @@ func (r *BaseRepository[T]) Search(field, keyword string) ([]T, error) {
 
 // ---------------- SOFT DELETE ----------------
 
-func (r *BaseRepository[T]) FindWithTrashed() ([]T, error) {
+func (r *BaseRepository[T, ID]) FindWithTrashed() ([]T, error) {
 	var items []T
 	err := r.db.Unscoped().Find(&items).Error
 	return items, err
 }
 
-func (r *BaseRepository[T]) OnlyTrashed() ([]T, error) {
+func (r *BaseRepository[T, ID]) OnlyTrashed() ([]T, error) {
 	var items []T
 	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").Find(&items).Error
 	return items, err
 }
 
-func (r *BaseRepository[T]) Restore(id uint, item T) error {
-	return r.db.Model(&item).Unscoped().Where("id = ?", id).Update("deleted_at", nil).Error
+func (r *BaseRepository[T, ID]) Restore(id ID, item T) error {
+	return r.db.Model(&item).Unscoped().Where(byID(id)).Update("deleted_at", nil).Error
 }
 
 // ---------------- EXTRA POWER ----------------
 
 // Join (raw join wrapper)
-func (r *BaseRepository[T]) Join(query string, args ...interface{}) ([]T, error) {
+func (r *BaseRepository[T, ID]) Join(query string, args ...interface{}) ([]T, error) {
 	var items []T
 	err := r.db.Joins(query, args...).Find(&items).Error
 	return items, err
 }
 
 // Pluck tek alanı çek
-func (r *BaseRepository[T]) Pluck(field string) ([]interface{}, error) {
+func (r *BaseRepository[T, ID]) Pluck(field string) ([]interface{}, error) {
 	var results []interface{}
 	var item T
 	err := r.db.Model(&item).Pluck(field, &results).Error
@@ -221,7 +221,7 @@ func (r *BaseRepository[T]) Pluck(field string) ([]interface{}, error) {
 }
 
 // Chunk – büyük dataset’i parça parça işleme
-func (r *BaseRepository[T]) Chunk(size int, fn func([]T) error) error {
+func (r *BaseRepository[T, ID]) Chunk(size int, fn func([]T) error) error {
 	var items []T
 	tx := r.db
 	for {
@@ -243,27 +243,33 @@ func (r *BaseRepository[T]) Chunk(size int, fn func([]T) error) error {
 }
 
 // DebugSQL Debug – son SQL
-func (r *BaseRepository[T]) DebugSQL() *gorm.DB {
+func (r *BaseRepository[T, ID]) DebugSQL() *gorm.DB {
 	return r.db.Debug()
 }
 
+// WithContext sonraki sorguları ctx'e bağlar. Tenant filtresi ve iptal ctx'ten
+// okunur; istek içindeki her sorgu bunun üzerinden yapılmalıdır.
+func (r *BaseRepository[T, ID]) WithContext(ctx context.Context) BaseRepositoryInterface[T, ID] {
+	return NewBaseRepository[T, ID](r.db.WithContext(ctx))
+}
+
 // Context destekli
-func (r *BaseRepository[T]) FindAllCtx(ctx context.Context) ([]T, error) {
+func (r *BaseRepository[T, ID]) FindAllCtx(ctx context.Context) ([]T, error) {
 	var items []T
 	err := r.db.WithContext(ctx).Find(&items).Error
 	return items, err
 }
 
 // Transaction içinde repo instance
-func (r *BaseRepository[T]) WithTransactionRepo(fn func(repo BaseRepositoryInterface[T]) error) error {
+func (r *BaseRepository[T, ID]) WithTransactionRepo(fn func(repo BaseRepositoryInterface[T, ID]) error) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		subRepo := NewBaseRepository[T](tx)
+		subRepo := NewBaseRepository[T, ID](tx)
 		return fn(subRepo)
 	})
 }
 
 // Upsert
-func (r *BaseRepository[T]) Upsert(item T, conflictColumns []string) error {
+func (r *BaseRepository[T, ID]) Upsert(item T, conflictColumns []string) error {
 	return r.db.Clauses(clause.OnConflict{
 		Columns:   toClauseColumns(conflictColumns),
 		UpdateAll: true,
@@ -279,23 +285,29 @@ func toClauseColumns(cols []string) []clause.Column {
 	return result
 }
 
+// byID birincil anahtar koşulu; kolon adı modelin şemasından çözülür,
+// böylece uuid, sayısal ya da farklı isimli anahtarlar aynı şekilde çalışır.
+func byID[ID comparable](id ID) clause.Eq {
+	return clause.Eq{Column: clause.PrimaryColumn, Value: id}
+}
+
 // ---------------- BUL / FİLTRELE ----------------
 
 // Field seçerek getir
-func (r *BaseRepository[T]) Select(fields []string) ([]T, error) {
+func (r *BaseRepository[T, ID]) Select(fields []string) ([]T, error) {
 	var items []T
 	err := r.db.Select(fields).Find(&items).Error
 	return items, err
 }
 
 // Belirli şartlarla ilk kaydı getir veya oluştur
-func (r *BaseRepository[T]) FirstOrCreate(where map[string]interface{}, defaults T) (T, error) {
+func (r *BaseRepository[T, ID]) FirstOrCreate(where map[string]interface{}, defaults T) (T, error) {
 	var item T
 	err := r.db.Where(where).FirstOrCreate(&item, defaults).Error
 	return item, err
 }
 
-func (r *BaseRepository[T]) Exists(where map[string]interface{}) (bool, error) {
+func (r *BaseRepository[T, ID]) Exists(where map[string]interface{}) (bool, error) {
 	var item T
 	err := r.db.Where(where).First(&item).Error
 	if err == gorm.ErrRecordNotFound {
@@ -305,13 +317,13 @@ func (r *BaseRepository[T]) Exists(where map[string]interface{}) (bool, error) {
 }
 
 // ---------------- UPDATE / UPSERT ----------------
-func (r *BaseRepository[T]) UpdateColumns(id uint, values map[string]interface{}) error {
+func (r *BaseRepository[T, ID]) UpdateColumns(id ID, values map[string]interface{}) error {
 	var item T
-	return r.db.Model(&item).Where("id = ?", id).Updates(values).Error
+	return r.db.Model(&item).Where(byID(id)).Updates(values).Error
 }
 
 // Distinct field değerleri
-func (r *BaseRepository[T]) Distinct(field string) ([]interface{}, error) {
+func (r *BaseRepository[T, ID]) Distinct(field string) ([]interface{}, error) {
 	var results []interface{}
 	var item T
 	err := r.db.Model(&item).Distinct(field).Pluck(field, &results).Error
@@ -319,14 +331,14 @@ func (r *BaseRepository[T]) Distinct(field string) ([]interface{}, error) {
 }
 
 // Scope destekli sorgu
-func (r *BaseRepository[T]) WithScopes(scopes ...func(*gorm.DB) *gorm.DB) ([]T, error) {
+func (r *BaseRepository[T, ID]) WithScopes(scopes ...func(*gorm.DB) *gorm.DB) ([]T, error) {
 	var items []T
 	err := r.db.Scopes(scopes...).Find(&items).Error
 	return items, err
 }
 
 // Preload ile ilişkili veriler
-func (r *BaseRepository[T]) WithPreload(preloads []string) ([]T, error) {
+func (r *BaseRepository[T, ID]) WithPreload(preloads []string) ([]T, error) {
 	var items []T
 	query := r.db
 	for _, preload := range preloads {
@@ -337,27 +349,33 @@ func (r *BaseRepository[T]) WithPreload(preloads []string) ([]T, error) {
 }
 
 // Raw SQL
-func (r *BaseRepository[T]) RawQuery(sql string, values ...interface{}) (*gorm.DB, error) {
+func (r *BaseRepository[T, ID]) RawQuery(sql string, values ...interface{}) (*gorm.DB, error) {
 	tx := r.db.Raw(sql, values...)
 	return tx, tx.Error
 }
 
 // Transaction
-func (r *BaseRepository[T]) Transaction(fn func(tx *gorm.DB) error) error {
+func (r *BaseRepository[T, ID]) Transaction(fn func(tx *gorm.DB) error) error {
 	return r.db.Transaction(fn)
 }
 
 // Lock for update
-func (r *BaseRepository[T]) FindForUpdate(id uint) (T, error) {
+func (r *BaseRepository[T, ID]) FindForUpdate(id ID) (T, error) {
 	var item T
-	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, id).Error
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where(byID(id)).First(&item).Error
 	return item, err
 }
 
 // ---------------- CONTEXT DESTEKLİ ----------------
 
-func (r *BaseRepository[T]) FindByIDCtx(ctx context.Context, id uint) (T, error) {
+func (r *BaseRepository[T, ID]) FindByIDCtx(ctx context.Context, id ID) (T, error) {
+	var item T
+	err := r.db.WithContext(ctx).Where(byID(id)).First(&item).Error
+	return item, err
+}
+
+func (r *BaseRepository[T, ID]) FirstCtx(ctx context.Context, where map[string]interface{}) (T, error) {
 	var item T
-	err := r.db.WithContext(ctx).First(&item, id).Error
+	err := r.db.WithContext(ctx).Where(where).First(&item).Error
 	return item, err
 }
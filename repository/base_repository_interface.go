@@ -6,13 +6,13 @@ import (
 	"gorm.io/gorm"
 )
 
-type BaseRepositoryInterface[T any] interface {
+type BaseRepositoryInterface[T any, ID comparable] interface {
 	FindAll() ([]T, error)
-	FindByID(id uint) (T, error)
+	FindByID(id ID) (T, error)
 	Create(item *T) error
 	Update(item T) (T, error)
-	Delete(id uint, item T) error
-	HardDelete(id uint, item T) error
+	Delete(id ID, item T) error
+	HardDelete(id ID, item T) error
 
 	UpdateWhere(where map[string]interface{}, values map[string]interface{}) error
 	DeleteWhere(where map[string]interface{}) error
@@ -40,18 +40,20 @@ type BaseRepositoryInterface[T any] interface {
 
 	FindWithTrashed() ([]T, error)
 	OnlyTrashed() ([]T, error)
-	Restore(id uint, item T) error
+	Restore(id ID, item T) error
 
 	Join(query string, args ...interface{}) ([]T, error)
 	Pluck(field string) ([]interface{}, error)
 	Chunk(size int, fn func([]T) error) error
 	DebugSQL() *gorm.DB
 
+	WithContext(ctx context.Context) BaseRepositoryInterface[T, ID]
 	FindAllCtx(ctx context.Context) ([]T, error)
-	FindByIDCtx(ctx context.Context, id uint) (T, error)
+	FindByIDCtx(ctx context.Context, id ID) (T, error)
+	FirstCtx(ctx context.Context, where map[string]interface{}) (T, error)
 
-	WithTransactionRepo(fn func(repo BaseRepositoryInterface[T]) error) error
+	WithTransactionRepo(fn func(repo BaseRepositoryInterface[T, ID]) error) error
 
 	Upsert(item T, conflictColumns []string) error
-	FindForUpdate(id uint) (T, error)
+	FindForUpdate(id ID) (T, error)
 }
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+
+	"gorm.io/gorm"
+)
+
+type IdentityRepository struct {
+	*BaseRepository[entity.Identity, string]
+}
+
+func NewIdentityRepository(db *gorm.DB) *IdentityRepository {
+	return &IdentityRepository{
+		BaseRepository: NewBaseRepository[entity.Identity, string](db),
+	}
+}
+
+func (r *IdentityRepository) FindBySubject(provider, subject string) (*entity.Identity, error) {
+	var identity entity.Identity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *IdentityRepository) ListForUser(userID string) ([]entity.Identity, error) {
+	var identities []entity.Identity
+	err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&identities).Error
+	return identities, err
+}
+
+// DeleteForUser kullanıcının kendi identity'sini siler; başkasına aitse false döner.
+func (r *IdentityRepository) DeleteForUser(userID, id string) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&entity.Identity{})
+	return result.RowsAffected > 0, result.Error
+}
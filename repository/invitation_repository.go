@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvitationRepository davetler tenant'a aittir; FindPending dışındaki
+// metodlar ctx'teki tenant'la sınırlıdır.
+type InvitationRepository struct {
+	*BaseRepository[entity.Invitation, string]
+}
+
+func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
+	return &InvitationRepository{
+		BaseRepository: NewBaseRepository[entity.Invitation, string](db),
+	}
+}
+
+// FindPending bekleyen ve süresi dolmamış daveti organizasyonuyla birlikte getirir.
+// Token'ın hangi tenant'a ait olduğu bilinmediğinden ctx tenant.Unscoped olmalıdır.
+func (r *InvitationRepository) FindPending(ctx context.Context, hash string, now time.Time) (*entity.Invitation, error) {
+	var invitation entity.Invitation
+	err := r.db.WithContext(ctx).Preload("Organization").
+		Where("token_hash = ? AND status = ? AND expires_at > ?", hash, entity.InvitationPending, now).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListInvitations tenant'ın davetlerini davet edenle birlikte, yeniden eskiye getirir.
+func (r *InvitationRepository) ListInvitations(ctx context.Context) ([]entity.Invitation, error) {
+	var invitations []entity.Invitation
+	err := r.db.WithContext(ctx).Preload("InvitedBy").Order("created_at DESC").Find(&invitations).Error
+	return invitations, err
+}
+
+// Rotate bekleyen davetin token'ını ve süresini yeniler; eski link geçersiz olur.
+// Davet bekleyen durumda değilse false döner.
+func (r *InvitationRepository) Rotate(ctx context.Context, id, hash string, expiresAt, sentAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Invitation{}).
+		Where("id = ? AND status = ?", id, entity.InvitationPending).
+		Updates(map[string]interface{}{"token_hash": hash, "expires_at": expiresAt, "sent_at": sentAt})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Revoke bekleyen daveti iptal eder. Davet bekleyen durumda değilse false döner.
+func (r *InvitationRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Invitation{}).
+		Where("id = ? AND status = ?", id, entity.InvitationPending).
+		Update("status", entity.InvitationRevoked)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Accept daveti kabul edildi işaretler ve üyeliği aynı transaction'da oluşturur.
+// Davet bu arada kabul, iptal edilmiş ya da süresi dolmuşsa gorm.ErrRecordNotFound,
+// kullanıcı zaten üyeyse gorm.ErrDuplicatedKey döner.
+func (r *InvitationRepository) Accept(ctx context.Context, invitation *entity.Invitation, userID string, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return accept(tx, invitation, userID, now)
+	})
+}
+
+// Register davetle kayıt olan kullanıcıyı rolleriyle oluşturur ve daveti aynı
+// transaction'da kabul eder; davet kabul edilemezse kullanıcı da oluşmaz.
+func (r *InvitationRepository) Register(ctx context.Context, invitation *entity.Invitation, user *entity.User, roles []entity.Role, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(user).Association("Roles").Append(roles); err != nil {
+			return err
+		}
+		return accept(tx, invitation, user.ID, now)
+	})
+}
+
+func accept(tx *gorm.DB, invitation *entity.Invitation, userID string, now time.Time) error {
+	result := tx.Model(&entity.Invitation{}).
+		Where("id = ? AND token_hash = ? AND status = ? AND expires_at > ?",
+			invitation.ID, invitation.TokenHash, entity.InvitationPending, now).
+		Updates(map[string]interface{}{
+			"status":         entity.InvitationAccepted,
+			"accepted_by_id": userID,
+			"accepted_at":    now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return tx.Create(&entity.Membership{UserID: userID, Role: invitation.Role}).Error
+}
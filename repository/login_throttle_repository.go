@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepository struct {
+	*BaseRepository[entity.LoginThrottle, string]
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) *LoginThrottleRepository {
+	return &LoginThrottleRepository{
+		BaseRepository: NewBaseRepository[entity.LoginThrottle, string](db),
+	}
+}
+
+// FindByKeys verilen anahtarlardan kaydı olanları getirir.
+func (r *LoginThrottleRepository) FindByKeys(keys []string) ([]entity.LoginThrottle, error) {
+	var items []entity.LoginThrottle
+	err := r.db.Where("key IN ?", keys).Find(&items).Error
+	return items, err
+}
+
+// Increment anahtarın hata sayacını tek bir upsert ile artırır. Son hata
+// windowStart'tan eskiyse sayaç 1'den başlar.
+func (r *LoginThrottleRepository) Increment(key string, now, windowStart time.Time) (*entity.LoginThrottle, error) {
+	item := entity.LoginThrottle{Key: key, Failures: 1, LastFailedAt: now}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":       gorm.Expr("CASE WHEN login_throttles.last_failed_at < ? THEN 1 ELSE login_throttles.failures + 1 END", windowStart),
+			"last_failed_at": now,
+		}),
+	}).Create(&item).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Where("key = ?", key).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *LoginThrottleRepository) Lock(key string, until time.Time) error {
+	return r.db.Model(&entity.LoginThrottle{}).
+		Where("key = ?", key).
+		Update("locked_until", until).Error
+}
+
+func (r *LoginThrottleRepository) DeleteKeys(keys []string) error {
+	return r.db.Where("key IN ?", keys).Delete(&entity.LoginThrottle{}).Error
+}
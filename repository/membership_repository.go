@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"go-initial-project/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MembershipRepository üyelikler tenant'a aittir; tüm metodlar ctx'teki
+// tenant'la sınırlıdır.
+type MembershipRepository struct {
+	*BaseRepository[entity.Membership, string]
+}
+
+func NewMembershipRepository(db *gorm.DB) *MembershipRepository {
+	return &MembershipRepository{
+		BaseRepository: NewBaseRepository[entity.Membership, string](db),
+	}
+}
+
+// WithTransaction fn'i tek bir transaction içinde, tx'e bağlı bir repo ile çalıştırır.
+func (r *MembershipRepository) WithTransaction(ctx context.Context, fn func(repo *MembershipRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewMembershipRepository(tx))
+	})
+}
+
+// FindForUser kullanıcının aktif tenant'taki üyeliğini getirir.
+func (r *MembershipRepository) FindForUser(ctx context.Context, userID string) (*entity.Membership, error) {
+	var membership entity.Membership
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&membership).Error; err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// FindForUserForUpdate FindForUser gibidir; satırı transaction sonuna kadar kilitler.
+func (r *MembershipRepository) FindForUserForUpdate(ctx context.Context, userID string) (*entity.Membership, error) {
+	var membership entity.Membership
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// LockOwners tenant'ın owner üyeliklerini kilitleyerek getirir; son owner'ın
+// eşzamanlı iki istekle düşürülmesini engeller.
+func (r *MembershipRepository) LockOwners(ctx context.Context) ([]entity.Membership, error) {
+	var owners []entity.Membership
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ?", entity.OrgRoleOwner).
+		Find(&owners).Error
+	return owners, err
+}
+
+// ListMembers tenant'ın üyelerini kullanıcılarıyla birlikte getirir.
+func (r *MembershipRepository) ListMembers(ctx context.Context) ([]entity.Membership, error) {
+	var memberships []entity.Membership
+	err := r.db.WithContext(ctx).Preload("User").Order("created_at").Find(&memberships).Error
+	return memberships, err
+}
+
+func (r *MembershipRepository) UpdateRole(ctx context.Context, userID, role string) error {
+	return r.db.WithContext(ctx).Model(&entity.Membership{}).
+		Where("user_id = ?", userID).
+		Update("role", role).Error
+}
+
+func (r *MembershipRepository) Remove(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.Membership{}).Error
+}
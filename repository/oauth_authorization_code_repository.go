@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OAuthAuthorizationCodeRepository struct {
+	*BaseRepository[entity.OAuthAuthorizationCode, string]
+}
+
+func NewOAuthAuthorizationCodeRepository(db *gorm.DB) *OAuthAuthorizationCodeRepository {
+	return &OAuthAuthorizationCodeRepository{
+		BaseRepository: NewBaseRepository[entity.OAuthAuthorizationCode, string](db),
+	}
+}
+
+// Consume kullanılmamış ve süresi dolmamış kodu tek bir UPDATE ile kullanılmış
+// işaretler; aynı kodla eşzamanlı iki istekten sadece biri başarılı olur.
+func (r *OAuthAuthorizationCodeRepository) Consume(hash string, now time.Time) (*entity.OAuthAuthorizationCode, error) {
+	result := r.db.Model(&entity.OAuthAuthorizationCode{}).
+		Where("code_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.FindByHash(hash)
+}
+
+func (r *OAuthAuthorizationCodeRepository) FindByHash(hash string) (*entity.OAuthAuthorizationCode, error) {
+	var code entity.OAuthAuthorizationCode
+	if err := r.db.Where("code_hash = ?", hash).First(&code).Error; err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+
+	"gorm.io/gorm"
+)
+
+type OAuthClientRepository struct {
+	*BaseRepository[entity.OAuthClient, string]
+}
+
+func NewOAuthClientRepository(db *gorm.DB) *OAuthClientRepository {
+	return &OAuthClientRepository{
+		BaseRepository: NewBaseRepository[entity.OAuthClient, string](db),
+	}
+}
+
+// FindByClientID token ve introspection isteklerinde çağrılır; bulunamazsa
+// log basmamak için First yerine Find kullanır.
+func (r *OAuthClientRepository) FindByClientID(id string) (*entity.OAuthClient, error) {
+	var clients []entity.OAuthClient
+	if err := r.db.Where("id = ?", id).Limit(1).Find(&clients).Error; err != nil {
+		return nil, err
+	}
+	if len(clients) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &clients[0], nil
+}
+
+func (r *OAuthClientRepository) List() ([]entity.OAuthClient, error) {
+	var clients []entity.OAuthClient
+	err := r.db.Order("created_at DESC").Find(&clients).Error
+	return clients, err
+}
+
+// DeleteClient client'ı, onaylarını ve kullanılmamış kodlarını siler.
+func (r *OAuthClientRepository) DeleteClient(id string) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&entity.OAuthClient{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected > 0
+		if err := tx.Where("client_id = ?", id).Delete(&entity.OAuthConsent{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("client_id = ?", id).Delete(&entity.OAuthAuthorizationCode{}).Error
+	})
+	return deleted, err
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OAuthConsentRepository struct {
+	*BaseRepository[entity.OAuthConsent, string]
+}
+
+func NewOAuthConsentRepository(db *gorm.DB) *OAuthConsentRepository {
+	return &OAuthConsentRepository{
+		BaseRepository: NewBaseRepository[entity.OAuthConsent, string](db),
+	}
+}
+
+// Find kayıt yoksa nil, nil döner.
+func (r *OAuthConsentRepository) Find(userID, clientID string) (*entity.OAuthConsent, error) {
+	var consents []entity.OAuthConsent
+	if err := r.db.Where("user_id = ? AND client_id = ?", userID, clientID).Limit(1).Find(&consents).Error; err != nil {
+		return nil, err
+	}
+	if len(consents) == 0 {
+		return nil, nil
+	}
+	return &consents[0], nil
+}
+
+// Save (user, client) için onayı oluşturur veya scope'larını günceller.
+func (r *OAuthConsentRepository) Save(consent *entity.OAuthConsent) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+	}).Create(consent).Error
+}
+
+func (r *OAuthConsentRepository) ListForUser(userID string) ([]entity.OAuthConsent, error) {
+	var consents []entity.OAuthConsent
+	err := r.db.Where("user_id = ?", userID).Order("updated_at DESC").Find(&consents).Error
+	return consents, err
+}
+
+// DeleteForUser kullanıcının client'a verdiği onayı siler; yoksa false döner.
+func (r *OAuthConsentRepository) DeleteForUser(userID, clientID string) (bool, error) {
+	result := r.db.Where("user_id = ? AND client_id = ?", userID, clientID).Delete(&entity.OAuthConsent{})
+	return result.RowsAffected > 0, result.Error
+}
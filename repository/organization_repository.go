@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"go-initial-project/entity"
+	"go-initial-project/tenant"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	*BaseRepository[entity.Organization, string]
+}
+
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{
+		BaseRepository: NewBaseRepository[entity.Organization, string](db),
+	}
+}
+
+// ListForUser kullanıcının üyeliklerini organizasyonlarıyla birlikte getirir.
+// Sorgu kullanıcının kendi üyelikleriyle sınırlı olduğundan tenant filtresi
+// gerekmez.
+func (r *OrganizationRepository) ListForUser(ctx context.Context, userID string) ([]entity.UserOrganization, error) {
+	var orgs []entity.UserOrganization
+	err := r.db.WithContext(ctx).
+		Table("organizations").
+		Select("organizations.*, memberships.role").
+		Joins("JOIN memberships ON memberships.organization_id = organizations.id").
+		Where("memberships.user_id = ? AND organizations.deleted_at IS NULL", userID).
+		Order("organizations.name").
+		Scan(&orgs).Error
+	return orgs, err
+}
+
+// CreateWithOwner organizasyonu ve owner üyeliğini tek transaction'da oluşturur.
+func (r *OrganizationRepository) CreateWithOwner(ctx context.Context, org *entity.Organization, owner *entity.Membership) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.WithContext(tenant.NewContext(ctx, org.ID)).Create(owner).Error
+	})
+}
+
+// DeleteWithMemberships organizasyonu siler (soft), tüm üyelikleri kaldırır ve
+// bekleyen davetleri iptal eder; silinen organizasyona davetle katılınamaz.
+func (r *OrganizationRepository) DeleteWithMemberships(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(tenant.Column+" = ?", id).Delete(&entity.Membership{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Invitation{}).
+			Where(tenant.Column+" = ? AND status = ?", id, entity.InvitationPending).
+			Update("status", entity.InvitationRevoked).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Organization{}, "id = ?", id).Error
+	})
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetTokenRepository struct {
+	*BaseRepository[entity.PasswordResetToken, string]
+}
+
+func NewPasswordResetTokenRepository(db *gorm.DB) *PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{
+		BaseRepository: NewBaseRepository[entity.PasswordResetToken, string](db),
+	}
+}
+
+// FindActive kullanılmamış ve süresi dolmamış token'ı tüketmeden döner.
+func (r *PasswordResetTokenRepository) FindActive(hash string, now time.Time) (*entity.PasswordResetToken, error) {
+	var token entity.PasswordResetToken
+	err := r.db.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Consume kullanılmamış ve süresi dolmamış token'ı tek bir UPDATE ile kullanılmış
+// işaretler; aynı token ile eşzamanlı iki istekten sadece biri başarılı olur.
+func (r *PasswordResetTokenRepository) Consume(hash string, now time.Time) (*entity.PasswordResetToken, error) {
+	result := r.db.Model(&entity.PasswordResetToken{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var token entity.PasswordResetToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// InvalidateForUser kullanıcının henüz kullanılmamış tüm token'larını kullanılmış işaretler.
+func (r *PasswordResetTokenRepository) InvalidateForUser(userID string, at time.Time) error {
+	return r.db.Model(&entity.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PhoneVerificationRepository struct {
+	*BaseRepository[entity.PhoneVerification, string]
+}
+
+func NewPhoneVerificationRepository(db *gorm.DB) *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{
+		BaseRepository: NewBaseRepository[entity.PhoneVerification, string](db),
+	}
+}
+
+// LatestForUser kullanıcıya en son gönderilen kodu döner (tekrar gönderim limiti için).
+func (r *PhoneVerificationRepository) LatestForUser(userID string) (*entity.PhoneVerification, error) {
+	var verification entity.PhoneVerification
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&verification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// FindActive numaraya gönderilmiş, kullanılmamış ve süresi dolmamış son kodu döner.
+func (r *PhoneVerificationRepository) FindActive(userID, phone string, now time.Time) (*entity.PhoneVerification, error) {
+	var verification entity.PhoneVerification
+	err := r.db.Where("user_id = ? AND phone = ? AND used_at IS NULL AND expires_at > ?", userID, phone, now).
+		Order("created_at DESC").
+		First(&verification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// RecordAttempt deneme sayısını limit aşılmadıysa atomik olarak artırır.
+// Limit dolduysa false döner; eşzamanlı isteklerle limit aşılamaz.
+func (r *PhoneVerificationRepository) RecordAttempt(id string, maxAttempts int) (bool, error) {
+	result := r.db.Model(&entity.PhoneVerification{}).
+		Where("id = ? AND used_at IS NULL AND attempts < ?", id, maxAttempts).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected > 0, result.Error
+}
+
+// Consume kodu kullanılmış işaretler; aynı kodla eşzamanlı iki istekten sadece biri başarılı olur.
+func (r *PhoneVerificationRepository) Consume(id string, now time.Time) (bool, error) {
+	result := r.db.Model(&entity.PhoneVerification{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateForUser kullanıcının henüz kullanılmamış tüm kodlarını kullanılmış işaretler.
+func (r *PhoneVerificationRepository) InvalidateForUser(userID string, at time.Time) error {
+	return r.db.Model(&entity.PhoneVerification{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository struct {
+	*BaseRepository[entity.RecoveryCode, uint]
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		BaseRepository: NewBaseRepository[entity.RecoveryCode, uint](db),
+	}
+}
+
+// ReplaceForUser kullanıcının eski kodlarını silip yenilerini kaydeder.
+func (r *RecoveryCodeRepository) ReplaceForUser(userID string, hashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]entity.RecoveryCode, len(hashes))
+		for i, hash := range hashes {
+			codes[i] = entity.RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Consume kullanılmamış kodu tek bir UPDATE ile kullanılmış işaretler.
+func (r *RecoveryCodeRepository) Consume(userID, hash string, now time.Time) (bool, error) {
+	result := r.db.Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *RecoveryCodeRepository) DeleteForUser(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error
+}
+
+func (r *RecoveryCodeRepository) CountUnused(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenRepository struct {
+	*BaseRepository[entity.RefreshToken, string]
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		BaseRepository: NewBaseRepository[entity.RefreshToken, string](db),
+	}
+}
+
+// WithTransaction fn'i tek bir transaction içinde, tx'e bağlı bir repo ile çalıştırır.
+func (r *RefreshTokenRepository) WithTransaction(fn func(repo *RefreshTokenRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRefreshTokenRepository(tx))
+	})
+}
+
+// FindByHashForUpdate satırı kilitleyerek getirir; eşzamanlı iki refresh isteği
+// aynı token'ı iki kez rotate edemez.
+func (r *RefreshTokenRepository) FindByHashForUpdate(hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", hash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) FindByHash(hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeFamily ailedeki henüz iptal edilmemiş tüm token'ları iptal eder.
+func (r *RefreshTokenRepository) RevokeFamily(familyID string, at time.Time) error {
+	return r.db.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+// RevokeAllForUser kullanıcının tüm aktif refresh token'larını iptal eder.
+func (r *RefreshTokenRepository) RevokeAllForUser(userID string, at time.Time) error {
+	return r.db.Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
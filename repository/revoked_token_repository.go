@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedTokenRepository struct {
+	*BaseRepository[entity.RevokedToken, string]
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		BaseRepository: NewBaseRepository[entity.RevokedToken, string](db),
+	}
+}
+
+// FindActive süresi dolmamış iptal kaydını getirir. Her istekte çağrıldığı için
+// First yerine Find kullanılır; kayıt yoksa gorm log'u kirletilmez.
+func (r *RevokedTokenRepository) FindActive(key string, now time.Time) (*entity.RevokedToken, error) {
+	var token entity.RevokedToken
+	result := r.db.Where("key = ? AND expires_at > ?", key, now).Limit(1).Find(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &token, nil
+}
+
+func (r *RevokedTokenRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&entity.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
+
+// InsertIfAbsent key için aktif bir kayıt yoksa token'ı yazar ve true döner.
+// Süresi dolmuş ama henüz temizlenmemiş kayıt tek sorguda üzerine yazılır;
+// eşzamanlı çağrılardan yalnızca biri true alır.
+func (r *RevokedTokenRepository) InsertIfAbsent(token entity.RevokedToken) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at", "expires_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "revoked_tokens.expires_at <= ?", Vars: []interface{}{token.RevokedAt}},
+		}},
+	}).Create(&token)
+	return result.RowsAffected == 1, result.Error
+}
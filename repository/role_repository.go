@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	*BaseRepository[entity.Role, uint]
+}
+
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{
+		BaseRepository: NewBaseRepository[entity.Role, uint](db),
+	}
+}
+
+func (r *RoleRepository) FindByNames(names []string) ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.db.Where("name IN ?", names).Find(&roles).Error
+	return roles, err
+}
+
+// FindAllWithPermissions rol → izin eşlemesi için tüm rolleri izinleriyle getirir.
+func (r *RoleRepository) FindAllWithPermissions() ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.db.Preload("Permissions").Find(&roles).Error
+	return roles, err
+}
+
+// EnsureRole rol yoksa oluşturur ve izinlerini verilen listeyle eşitler.
+func (r *RoleRepository) EnsureRole(name, description string, permissionNames []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var permissions []entity.Permission
+		for _, permName := range permissionNames {
+			perm := entity.Permission{Name: permName}
+			if err := tx.Where(entity.Permission{Name: permName}).FirstOrCreate(&perm).Error; err != nil {
+				return err
+			}
+			permissions = append(permissions, perm)
+		}
+
+		role := entity.Role{Name: name}
+		if err := tx.Where(entity.Role{Name: name}).Attrs(entity.Role{Description: description}).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+		return tx.Model(&role).Association("Permissions").Replace(permissions)
+	})
+}
+
+func (r *RoleRepository) RoleNamesForUser(userID string) ([]string, error) {
+	var names []string
+	err := r.db.Table("roles").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Pluck("roles.name", &names).Error
+	return names, err
+}
+
+func (r *RoleRepository) AssignToUser(userID string, roles []entity.Role) error {
+	user := entity.User{ID: userID}
+	return r.db.Model(&user).Association("Roles").Append(roles)
+}
+
+func (r *RoleRepository) ReplaceForUser(userID string, roles []entity.Role) error {
+	user := entity.User{ID: userID}
+	return r.db.Model(&user).Association("Roles").Replace(roles)
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	*BaseRepository[entity.Session, string]
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{
+		BaseRepository: NewBaseRepository[entity.Session, string](db),
+	}
+}
+
+// ListActive kullanıcının iptal edilmemiş ve since'ten sonra görülmüş oturumlarını getirir.
+func (r *SessionRepository) ListActive(userID string, since time.Time) ([]entity.Session, error) {
+	var sessions []entity.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND last_seen_at > ?", userID, since).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Revoke kullanıcının aktif oturumunu iptal eder; oturum yoksa veya başkasına aitse false döner.
+func (r *SessionRepository) Revoke(userID, id string, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *SessionRepository) RevokeAllForUser(userID string, at time.Time) error {
+	return r.db.Model(&entity.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func (r *SessionRepository) Touch(id, ip string, at time.Time) error {
+	return r.db.Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"last_seen_at": at, "ip": ip}).Error
+}
+
+// SetOrganization aktif oturumun organizasyonunu değiştirir; oturum yoksa veya
+// başkasına aitse false döner.
+func (r *SessionRepository) SetOrganization(userID, id, organizationID string) (bool, error) {
+	result := r.db.Model(&entity.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("organization_id", organizationID)
+	return result.RowsAffected > 0, result.Error
+}
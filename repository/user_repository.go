@@ -7,12 +7,12 @@ import (
 )
 
 type UserRepository struct {
-	*BaseRepository[entity.User]
+	*BaseRepository[entity.User, string]
 }
 
 func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{
-		BaseRepository: NewBaseRepository[entity.User](db),
+		BaseRepository: NewBaseRepository[entity.User, string](db),
 	}
 }
 
@@ -23,3 +23,10 @@ func (ur *UserRepository) FindByEmail(email string) (*entity.User, error) {
 	}
 	return &user, nil
 }
+
+func (ur *UserRepository) AdvanceTOTPStep(userID string, step int64) (bool, error) {
+	result := ur.db.Model(&entity.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		UpdateColumn("totp_last_step", step)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"go-initial-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebAuthnCredentialRepository struct {
+	*BaseRepository[entity.WebAuthnCredential, string]
+}
+
+func NewWebAuthnCredentialRepository(db *gorm.DB) *WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{
+		BaseRepository: NewBaseRepository[entity.WebAuthnCredential, string](db),
+	}
+}
+
+func (r *WebAuthnCredentialRepository) ListForUser(userID string) ([]entity.WebAuthnCredential, error) {
+	var credentials []entity.WebAuthnCredential
+	err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&credentials).Error
+	return credentials, err
+}
+
+func (r *WebAuthnCredentialRepository) CountForUser(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.WebAuthnCredential{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// RecordUse başarılı login sonrası sayaç ve yedekleme durumunu günceller.
+func (r *WebAuthnCredentialRepository) RecordUse(id string, signCount uint32, backupState bool, at time.Time) error {
+	return r.db.Model(&entity.WebAuthnCredential{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sign_count":   signCount,
+			"backup_state": backupState,
+			"last_used_at": at,
+		}).Error
+}
+
+// DeleteForUser passkey başkasına aitse false döner.
+func (r *WebAuthnCredentialRepository) DeleteForUser(userID, id string) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&entity.WebAuthnCredential{})
+	return result.RowsAffected > 0, result.Error
+}
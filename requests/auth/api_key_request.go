@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"time"
+
+	"go-initial-project/validator"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"       validate:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes"     validate:"unique,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty"`
+}
+
+func (r *CreateAPIKeyRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
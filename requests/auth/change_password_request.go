@@ -0,0 +1,12 @@
+package auth
+
+import "go-initial-project/validator"
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password"     validate:"required"`
+}
+
+func (r *ChangePasswordRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
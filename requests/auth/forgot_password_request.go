@@ -0,0 +1,11 @@
+package auth
+
+import "go-initial-project/validator"
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (r *ForgotPasswordRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
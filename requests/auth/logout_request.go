@@ -0,0 +1,12 @@
+package auth
+
+import "go-initial-project/validator"
+
+// LogoutRequest refresh_token verilirse ilgili token ailesi de iptal edilir.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"omitempty"`
+}
+
+func (r *LogoutRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
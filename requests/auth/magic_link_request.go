@@ -0,0 +1,19 @@
+package auth
+
+import "go-initial-project/validator"
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (r *MagicLinkRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+type MagicLinkExchangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (r *MagicLinkExchangeRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,22 @@
+package auth
+
+import "go-initial-project/validator"
+
+// MFACodeRequest TOTP kodu veya recovery code taşır.
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,min=6,max=20"`
+}
+
+func (r *MFACodeRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// LoginMFARequest login'in ikinci adımı.
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code"      validate:"required,min=6,max=20"`
+}
+
+func (r *LoginMFARequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
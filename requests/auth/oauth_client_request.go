@@ -0,0 +1,16 @@
+package auth
+
+import "go-initial-project/validator"
+
+type CreateOAuthClientRequest struct {
+	Name         string   `json:"name"          validate:"required,min=2,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"dive,url"`
+	GrantTypes   []string `json:"grant_types"   validate:"required,min=1,dive,oneof=authorization_code client_credentials"`
+	Scopes       []string `json:"scopes"        validate:"dive,required"`
+	// Public secret tutamayan uygulamalar (SPA, mobil) içindir; PKCE zorunludur.
+	Public bool `json:"public"`
+}
+
+func (r *CreateOAuthClientRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,57 @@
+package auth
+
+import "go-initial-project/validator"
+
+// AuthorizeRequest client'ın frontend'e yönlendirdiği authorization isteği.
+// GET'te query'den, POST'ta JSON gövdeden okunur.
+type AuthorizeRequest struct {
+	ResponseType        string `form:"response_type"         json:"response_type"`
+	ClientID            string `form:"client_id"             json:"client_id"    validate:"required"`
+	RedirectURI         string `form:"redirect_uri"          json:"redirect_uri" validate:"required"`
+	Scope               string `form:"scope"                 json:"scope"`
+	State               string `form:"state"                 json:"state"`
+	CodeChallenge       string `form:"code_challenge"        json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+func (r *AuthorizeRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// AuthorizeDecisionRequest kullanıcının onay ekranındaki kararı.
+type AuthorizeDecisionRequest struct {
+	AuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+func (r *AuthorizeDecisionRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// TokenRequest /oauth/token gövdesi (application/x-www-form-urlencoded).
+// Client bilgileri Basic auth header'ında da gönderilebilir.
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"    validate:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+func (r *TokenRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// TokenLookupRequest introspection (RFC 7662) ve revocation (RFC 7009) gövdesi.
+type TokenLookupRequest struct {
+	Token         string `form:"token"           validate:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+func (r *TokenLookupRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
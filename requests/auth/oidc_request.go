@@ -0,0 +1,13 @@
+package auth
+
+import "go-initial-project/validator"
+
+// OIDCCallbackRequest IdP'nin redirect_uri'ye döndürdüğü code ve state.
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"  validate:"required"`
+	State string `json:"state" validate:"required"`
+}
+
+func (r *OIDCCallbackRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,11 @@
+package auth
+
+import "go-initial-project/validator"
+
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+func (r *VerifyPhoneRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,11 @@
+package auth
+
+import "go-initial-project/validator"
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func (r *RefreshRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
@@ -6,7 +6,9 @@ type RegisterRequest struct {
 	FirstName string `json:"first_name" validate:"required,min=2,max=50"`
 	LastName  string `json:"last_name"  validate:"required,min=2,max=50"`
 	Email     string `json:"email"      validate:"required,email"`
-	Password  string `json:"password"   validate:"required,min=6"`
+	Password  string `json:"password"   validate:"required"`
+	// InvitationToken davet e-postasındaki token; kayıt tamamlanınca davet kabul edilir.
+	InvitationToken string `json:"invitation_token,omitempty"`
 }
 
 func (r *RegisterRequest) Validate() error {
@@ -0,0 +1,11 @@
+package auth
+
+import "go-initial-project/validator"
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (r *ResendVerificationRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
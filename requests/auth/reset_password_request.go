@@ -0,0 +1,12 @@
+package auth
+
+import "go-initial-project/validator"
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"    validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+func (r *ResetPasswordRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,11 @@
+package auth
+
+import "go-initial-project/validator"
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (r *VerifyEmailRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
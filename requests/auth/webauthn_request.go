@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"encoding/json"
+	"go-initial-project/validator"
+)
+
+// WebAuthnFinishRegistrationRequest navigator.credentials.create() sonucunu taşır.
+// Session begin adımında dönen değerdir.
+type WebAuthnFinishRegistrationRequest struct {
+	Session    string          `json:"session"    validate:"required"`
+	Name       string          `json:"name"       validate:"max=100"`
+	Credential json.RawMessage `json:"credential" validate:"required" swaggertype:"object"`
+}
+
+func (r *WebAuthnFinishRegistrationRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// WebAuthnFinishLoginRequest navigator.credentials.get() sonucunu taşır.
+type WebAuthnFinishLoginRequest struct {
+	Session    string          `json:"session"    validate:"required"`
+	Credential json.RawMessage `json:"credential" validate:"required" swaggertype:"object"`
+}
+
+func (r *WebAuthnFinishLoginRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// WebAuthnBeginMFARequest login'in ikinci adımını passkey ile başlatır.
+type WebAuthnBeginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+func (r *WebAuthnBeginMFARequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// WebAuthnLoginMFARequest login'in ikinci adımını passkey ile tamamlar.
+type WebAuthnLoginMFARequest struct {
+	MFAToken   string          `json:"mfa_token"  validate:"required"`
+	Session    string          `json:"session"    validate:"required"`
+	Credential json.RawMessage `json:"credential" validate:"required" swaggertype:"object"`
+}
+
+func (r *WebAuthnLoginMFARequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,20 @@
+package org
+
+import "go-initial-project/validator"
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	Role  string `json:"role"  validate:"required,oneof=owner admin member"`
+}
+
+func (r *CreateInvitationRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (r *AcceptInvitationRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
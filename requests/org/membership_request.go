@@ -0,0 +1,11 @@
+package org
+
+import "go-initial-project/validator"
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+func (r *UpdateMemberRoleRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
@@ -0,0 +1,21 @@
+package org
+
+import "go-initial-project/validator"
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+	// Slug boşsa addan türetilir.
+	Slug string `json:"slug" validate:"omitempty,min=3,max=64,slug"`
+}
+
+func (r *CreateOrganizationRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+func (r *UpdateOrganizationRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
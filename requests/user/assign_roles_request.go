@@ -0,0 +1,11 @@
+package userrequests
+
+import "go-initial-project/validator"
+
+type AssignRolesRequest struct {
+	Roles []string `json:"roles" validate:"required,unique,dive,required"`
+}
+
+func (r *AssignRolesRequest) Validate() error {
+	return validator.Validate.Struct(r)
+}
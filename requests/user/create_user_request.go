@@ -6,7 +6,7 @@ type CreateUserRequest struct {
 	FirstName string `json:"first_name" validate:"required,min=2,max=50"`
 	LastName  string `json:"last_name"  validate:"required,min=2,max=50"`
 	Email     string `json:"email"      validate:"required,email"`
-	Phone     string `json:"phone"      validate:"required"`
+	Phone     string `json:"phone"      validate:"required,e164"`
 }
 
 func (r *CreateUserRequest) Validate() error {
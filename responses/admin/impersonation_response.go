@@ -0,0 +1,10 @@
+package admin
+
+import userres "go-initial-project/responses/user"
+
+type ImpersonationResponse struct {
+	Token          string               `json:"token"`
+	ExpiresIn      int64                `json:"expires_in"`
+	ImpersonatorID string               `json:"impersonator_id"`
+	User           userres.UserResponse `json:"user"`
+}
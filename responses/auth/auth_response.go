@@ -1,6 +1,8 @@
 package auth
 
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  any    `json:"user"` // istersen burada UserResponse kullanabilirsin
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	User         any    `json:"user"` // istersen burada UserResponse kullanabilirsin
 }
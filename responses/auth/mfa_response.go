@@ -0,0 +1,20 @@
+package auth
+
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCodePNG  string `json:"qr_code_png"` // data:image/png;base64,...
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAChallengeResponse şifre doğrulandı ama ikinci adım gerekiyorsa login'in cevabı.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	// Methods ikinci adımda kullanılabilecek yöntemler: "totp", "webauthn".
+	Methods []string `json:"methods"`
+}
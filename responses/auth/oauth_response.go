@@ -0,0 +1,67 @@
+package auth
+
+import "time"
+
+// OAuthTokenResponse RFC 6749 5.1 formatındadır.
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// OAuthErrorResponse RFC 6749 5.2 formatındadır.
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// IntrospectionResponse RFC 7662 2.2 formatındadır.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+type OAuthClientSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AuthorizationPromptResponse frontend'in onay ekranını çizmesi için.
+// ConsentRequired false ise frontend kullanıcıya sormadan onaylayabilir.
+type AuthorizationPromptResponse struct {
+	Client          OAuthClientSummary `json:"client"`
+	Scopes          []string           `json:"scopes"`
+	ConsentRequired bool               `json:"consent_required"`
+}
+
+type AuthorizationRedirectResponse struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+type OAuthConsentResponse struct {
+	Client    OAuthClientSummary `json:"client"`
+	Scopes    []string           `json:"scopes"`
+	GrantedAt time.Time          `json:"granted_at"`
+}
+
+type OAuthClientResponse struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Public       bool      `json:"public"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	GrantTypes   []string  `json:"grant_types"`
+	Scopes       []string  `json:"scopes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// OAuthClientCreatedResponse secret'ı sadece oluşturma anında bir kez döner.
+type OAuthClientCreatedResponse struct {
+	OAuthClientResponse
+	Secret string `json:"client_secret,omitempty"`
+}
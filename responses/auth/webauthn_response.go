@@ -0,0 +1,19 @@
+package auth
+
+import "time"
+
+// WebAuthnOptionsResponse tarayıcıdaki navigator.credentials.create()/get()
+// çağrısına verilecek seçenekler. Session finish isteğinde aynen geri gönderilir.
+type WebAuthnOptionsResponse struct {
+	Options any    `json:"options"`
+	Session string `json:"session"`
+}
+
+type WebAuthnCredentialResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Transports []string   `json:"transports"`
+	Synced     bool       `json:"synced"` // passkey cihazlar arası yedekleniyor mu
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package org
+
+import (
+	"go-initial-project/responses/user"
+	"time"
+)
+
+type InvitationResponse struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	// Status pending, accepted, revoked veya expired.
+	Status     string             `json:"status"`
+	InvitedBy  *user.UserResponse `json:"invited_by,omitempty"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	SentAt     time.Time          `json:"sent_at"`
+	AcceptedAt *time.Time         `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package org
+
+import (
+	"go-initial-project/responses/user"
+	"time"
+)
+
+type OrganizationResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	// Role isteği yapan kullanıcının bu organizasyondaki rolü.
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MemberResponse struct {
+	User     user.UserResponse `json:"user"`
+	Role     string            `json:"role"`
+	JoinedAt time.Time         `json:"joined_at"`
+}
+
+type SwitchOrganizationResponse struct {
+	Token          string `json:"token"`
+	ExpiresIn      int64  `json:"expires_in"`
+	OrganizationID string `json:"organization_id"`
+}
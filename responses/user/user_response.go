@@ -1,8 +1,13 @@
 package user
 
 type UserResponse struct {
-	ID        string `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Email     string `json:"email"`
+	ID            string `json:"id"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Phone         string `json:"phone,omitempty"`
+	PhoneVerified bool   `json:"phone_verified"`
+	// ImpersonatedBy sadece /me'de, impersonation token'ıyla istek yapılıyorsa dolar.
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
 }
@@ -8,13 +8,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(activityService *service.ActivityService, controllers ...controller.Controller) *gin.Engine {
+func SetupRouter(activityService *service.ActivityService, apiKeyService *service.APIKeyService, controllers ...controller.Controller) *gin.Engine {
 	r := gin.Default()
 	api := r.Group("/api")
 
 	// Tek middleware burada
 	r.Use(middleware.ActivityLogger(activityService))
 	api.Use(middleware.ActivityLogger(activityService)) // sadece burada
+	api.Use(middleware.APIKeyAuth(apiKeyService))
 
 	for _, c := range controllers {
 		c.RegisterRoutes(api)
@@ -1,10 +1,21 @@
 package service
 
 import (
+	"context"
 	"go-initial-project/entity"
+	"go-initial-project/principal"
 	"go-initial-project/repository"
+	"go-initial-project/tenant"
+	"log"
+	"time"
 )
 
+// ClientInfo güvenlik olaylarını kaydederken isteği yapan istemcinin bilgileri.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
 type ActivityService struct {
 	repo *repository.ActivityRepository
 }
@@ -16,3 +27,51 @@ func NewActivityService(repo *repository.ActivityRepository) *ActivityService {
 func (s *ActivityService) Log(activity *entity.Activity) error {
 	return s.repo.Create(activity)
 }
+
+// LogEvent HTTP isteğinden bağımsız güvenlik olaylarını (şifre sıfırlama vb.) kaydeder.
+// userID boş ise olay anonim kaydedilir.
+func (s *ActivityService) LogEvent(userID, action string, client ClientInfo) error {
+	return s.LogActorEvent(userID, "", action, client)
+}
+
+// LogActorEvent LogEvent gibidir; actorID olayı userID adına gerçekleştiren
+// kullanıcıdır (impersonation).
+func (s *ActivityService) LogActorEvent(userID, actorID, action string, client ClientInfo) error {
+	activity := &entity.Activity{
+		Action:    action,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		CreatedAt: time.Now(),
+	}
+	if userID != "" {
+		activity.UserID = &userID
+	}
+	if actorID != "" {
+		activity.ActorID = &actorID
+	}
+	return s.repo.Create(activity)
+}
+
+// LogScopeBypass tenant.Unscoped çağrılarını kaydeder; gerekçe Request
+// alanına yazılır. İşlemi yapan principal ctx'ten okunur.
+func (s *ActivityService) LogScopeBypass(ctx context.Context, reason string) {
+	activity := &entity.Activity{
+		Action:    "tenant_scope_bypassed",
+		Request:   reason,
+		CreatedAt: time.Now(),
+	}
+	if p, ok := principal.FromContext(ctx); ok {
+		if p.UserID != "" {
+			activity.UserID = &p.UserID
+		}
+		if p.ActorID != "" {
+			activity.ActorID = &p.ActorID
+		}
+	}
+	if organizationID, ok := tenant.FromContext(ctx); ok {
+		activity.OrganizationID = &organizationID
+	}
+	if err := s.repo.Create(activity); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyPrefix = "gip_"
+	// apiKeyTouchInterval last_used_at her istekte değil en fazla bu sıklıkta yazılır.
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("invalid or expired api key")
+	ErrAPIKeyScope   = errors.New("scope not allowed")
+	ErrAPIKeyExpiry  = errors.New("expires_at must be in the future")
+)
+
+// APIKeyPrincipal doğrulanmış API anahtarı ve sahibinin etkin izinleri.
+// Permissions anahtarın scope'ları ile sahibinin güncel izinlerinin kesişimidir.
+type APIKeyPrincipal struct {
+	Key         *entity.APIKey
+	User        *entity.User
+	Roles       []string
+	Permissions []string
+}
+
+type APIKeyService struct {
+	repo            *repository.APIKeyRepository
+	userService     *UserService
+	roleService     *RoleService
+	activityService *ActivityService
+}
+
+func NewAPIKeyService(
+	repo *repository.APIKeyRepository,
+	userService *UserService,
+	roleService *RoleService,
+	activityService *ActivityService,
+) *APIKeyService {
+	return &APIKeyService{
+		repo:            repo,
+		userService:     userService,
+		roleService:     roleService,
+		activityService: activityService,
+	}
+}
+
+// Create yeni anahtar üretir ve ham değeri sadece bir kez döner. Scope'lar
+// sahibinin şu an sahip olduğu izinlerle sınırlıdır.
+func (s *APIKeyService) Create(userID, name string, scopes []string, expiresAt *time.Time, client ClientInfo) (*entity.APIKey, string, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", ErrAPIKeyExpiry
+	}
+	roles, err := s.roleService.RoleNamesForUser(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	granted, err := s.roleService.PermissionsFor(roles)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return nil, "", fmt.Errorf("%w: %s", ErrAPIKeyScope, scope)
+		}
+	}
+
+	prefix, err := randomPrefix()
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &entity.APIKey{
+		UserID:     userID,
+		Name:       name,
+		Prefix:     prefix,
+		SecretHash: hashToken(secret),
+		Scopes:     scopes,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.repo.Create(key); err != nil {
+		return nil, "", err
+	}
+
+	s.logEvent(userID, "api_key_created", client)
+	return key, prefix + "." + secret, nil
+}
+
+func (s *APIKeyService) List(userID string) ([]entity.APIKey, error) {
+	return s.repo.ListForUser(userID)
+}
+
+// Revoke kullanıcının kendi anahtarını iptal eder. Anahtar yoksa, başkasına
+// aitse veya zaten iptal edilmişse false döner.
+func (s *APIKeyService) Revoke(userID, id string, client ClientInfo) (bool, error) {
+	ok, err := s.repo.Revoke(userID, id, time.Now())
+	if err != nil || !ok {
+		return ok, err
+	}
+	s.logEvent(userID, "api_key_revoked", client)
+	return true, nil
+}
+
+// Authenticate ham anahtarı doğrular ve sahibini, etkin izinleriyle birlikte döner.
+func (s *APIKeyService) Authenticate(raw string) (*APIKeyPrincipal, error) {
+	prefix, secret, ok := strings.Cut(raw, ".")
+	if !ok || !strings.HasPrefix(prefix, apiKeyPrefix) || secret == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.FindByPrefix(prefix)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(hashToken(secret))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, ErrInvalidAPIKey
+	}
+	// Silinmiş kullanıcının anahtarları da geçersizdir.
+	owner, err := s.userService.First(map[string]interface{}{"id": key.UserID})
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	roles, err := s.roleService.RoleNamesForUser(key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	granted, err := s.roleService.PermissionsFor(roles)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if slices.Contains(granted, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.repo.Touch(key.ID, now); err != nil {
+			log.Printf("⚠️ failed to update api key last_used_at: %v", err)
+		}
+	}
+
+	return &APIKeyPrincipal{Key: key, User: &owner, Roles: roles, Permissions: permissions}, nil
+}
+
+func (s *APIKeyService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
+
+func randomPrefix() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(b), nil
+}
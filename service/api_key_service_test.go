@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/repository"
+	"testing"
+	"time"
+)
+
+func newAPIKeyService(env *testEnv) *APIKeyService {
+	return NewAPIKeyService(repository.NewAPIKeyRepository(env.db), env.users, env.roles, env.activities)
+}
+
+func TestCreateAPIKeyRejectsPastExpiry(t *testing.T) {
+	env := newTestEnv(t)
+	keys := newAPIKeyService(env)
+	user := env.createUser(t, "expiry@example.com")
+
+	past := time.Now().Add(-time.Minute)
+	if _, _, err := keys.Create(user.ID, "expired", nil, &past, ClientInfo{}); !errors.Is(err, ErrAPIKeyExpiry) {
+		t.Fatalf("err = %v, want ErrAPIKeyExpiry", err)
+	}
+
+	future := time.Now().Add(time.Hour)
+	_, raw, err := keys.Create(user.ID, "valid", nil, &future, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Authenticate(raw); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRevokeUserTokensRevokesAPIKeys(t *testing.T) {
+	env := newTestEnv(t)
+	keys := newAPIKeyService(env)
+	user := env.createUser(t, "reset-keys@example.com")
+	other := env.createUser(t, "other-keys@example.com")
+
+	_, raw, err := keys.Create(user.ID, "ci", nil, nil, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherRaw, err := keys.Create(other.ID, "ci", nil, nil, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.tokens.RevokeUserTokens(user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Authenticate(raw); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("err = %v, want ErrInvalidAPIKey", err)
+	}
+	if _, err := keys.Authenticate(otherRaw); err != nil {
+		t.Fatalf("other user's key revoked: %v", err)
+	}
+}
+
+func TestPasswordChangeRevokesAPIKeys(t *testing.T) {
+	env := newTestEnv(t)
+	keys := newAPIKeyService(env)
+	apiKeys := repository.NewAPIKeyRepository(env.db)
+	passwords := NewPasswordService(env.users, env.sessions, env.activities, newLoginThrottleService(env), repository.NewPasswordResetTokenRepository(env.db), apiKeys)
+
+	user := env.createUser(t, "change-keys@example.com")
+	if err := env.users.UpdatePassword(user.ID, "old-Passw0rd-quartz"); err != nil {
+		t.Fatal(err)
+	}
+	user = reloadUser(t, env, user.ID)
+
+	_, raw, err := keys.Create(user.ID, "ci", nil, nil, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := passwords.Change(user, "old-Passw0rd-quartz", "new-Passw0rd-quartz", "", ClientInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Authenticate(raw); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("err = %v, want ErrInvalidAPIKey", err)
+	}
+}
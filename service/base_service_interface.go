@@ -1,15 +1,20 @@
 package service
 
-type BaseServiceInterface[T any] interface {
+import "context"
+
+type BaseServiceInterface[T any, ID comparable] interface {
+	WithContext(ctx context.Context) BaseServiceInterface[T, ID]
 	GetAll() ([]T, error)
-	GetByID(id uint) (T, error)
+	GetByID(id ID) (T, error)
 	Create(item T) (T, error)
 	Update(item T) (T, error)
-	Delete(id uint, item T) error
-	HardDelete(id uint, item T) error
+	First(where map[string]interface{}) (T, error)
+	UpdateWhere(where map[string]interface{}, values map[string]interface{}) error
+	Delete(id ID, item T) error
+	HardDelete(id ID, item T) error
 	Paginate(offset, limit int) ([]T, int64, error)
 	Search(field, keyword string) ([]T, error)
 	FindWithTrashed() ([]T, error)
 	OnlyTrashed() ([]T, error)
-	Restore(id uint, item T) error
+	Restore(id ID, item T) error
 }
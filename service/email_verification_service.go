@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/mailer"
+	"log"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrVerificationThrottled = errors.New("verification e-mail was sent recently")
+
+type EmailVerificationService struct {
+	userService     *UserService
+	tokenService    *TokenService
+	activityService *ActivityService
+	mailer          mailer.Mailer
+}
+
+func NewEmailVerificationService(
+	userService *UserService,
+	tokenService *TokenService,
+	activityService *ActivityService,
+	mail mailer.Mailer,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		userService:     userService,
+		tokenService:    tokenService,
+		activityService: activityService,
+		mailer:          mail,
+	}
+}
+
+// Send imzalı doğrulama linkini gönderir. Son gönderimin üzerinden
+// VerificationResendInterval geçmediyse ErrVerificationThrottled döner.
+func (s *EmailVerificationService) Send(user *entity.User) error {
+	if user.EmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	interval := config.AppConfig.Auth.VerificationResendInterval
+	if user.VerificationSentAt != nil && now.Sub(*user.VerificationSentAt) < interval {
+		return ErrVerificationThrottled
+	}
+
+	ttl := config.AppConfig.Auth.EmailVerificationTTL
+	token, err := s.tokenService.SignPurposeToken(PurposeEmailVerification, user.ID, user.Email, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", config.AppConfig.App.FrontendURL, url.QueryEscape(token))
+	if err := s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your e-mail address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your e-mail address by opening the link below. It expires in %s.\n\n%s\n",
+			user.FirstName, ttl, link,
+		),
+	}); err != nil {
+		return err
+	}
+
+	return s.userService.UpdateWhere(
+		map[string]interface{}{"id": user.ID},
+		map[string]interface{}{"verification_sent_at": now},
+	)
+}
+
+// Resend e-posta adresine göre tekrar gönderir. Kayıtlı olmayan ya da zaten
+// doğrulanmış adresler için sessizce nil döner.
+func (s *EmailVerificationService) Resend(email string) error {
+	user, err := s.userService.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.Send(user)
+}
+
+// Verify linkteki token'ı doğrular ve kullanıcıyı doğrulanmış işaretler.
+// Token üretildikten sonra e-posta değiştiyse link geçersiz sayılır.
+func (s *EmailVerificationService) Verify(token string, client ClientInfo) (*entity.User, error) {
+	claims, err := s.tokenService.ParsePurposeToken(PurposeEmailVerification, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.First(map[string]interface{}{"id": claims.Subject})
+	if err != nil || user.Email != claims.Email {
+		return nil, ErrInvalidPurposeToken
+	}
+	if user.EmailVerified() {
+		return &user, nil
+	}
+
+	now := time.Now()
+	if err := s.userService.UpdateWhere(
+		map[string]interface{}{"id": user.ID},
+		map[string]interface{}{"email_verified_at": now},
+	); err != nil {
+		return nil, err
+	}
+	user.EmailVerifiedAt = &now
+
+	if err := s.activityService.LogEvent(user.ID, "email_verified", client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+	return &user, nil
+}
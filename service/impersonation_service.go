@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"log"
+	"slices"
+)
+
+var (
+	ErrImpersonateSelf  = errors.New("you cannot impersonate yourself")
+	ErrImpersonateAdmin = errors.New("administrators cannot be impersonated")
+)
+
+type ImpersonationResult struct {
+	Token     string
+	ExpiresIn int64
+	User      *entity.User
+}
+
+type ImpersonationService struct {
+	userService     *UserService
+	roleService     *RoleService
+	tokenService    *TokenService
+	activityService *ActivityService
+}
+
+func NewImpersonationService(
+	userService *UserService,
+	roleService *RoleService,
+	tokenService *TokenService,
+	activityService *ActivityService,
+) *ImpersonationService {
+	return &ImpersonationService{
+		userService:     userService,
+		roleService:     roleService,
+		tokenService:    tokenService,
+		activityService: activityService,
+	}
+}
+
+// Start actorID'nin targetID adına kullanacağı token'ı üretir. Admin rolündeki
+// hesaplar impersonate edilemez; aksi halde yetki yükseltmeye kapı açılır.
+// Hedef kullanıcı bulunamazsa gorm.ErrRecordNotFound döner.
+func (s *ImpersonationService) Start(actorID, targetID string, client ClientInfo) (*ImpersonationResult, error) {
+	if actorID == targetID {
+		return nil, ErrImpersonateSelf
+	}
+	user, err := s.userService.First(map[string]interface{}{"id": targetID})
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.roleService.RoleNamesForUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(roles, entity.RoleAdmin) {
+		return nil, ErrImpersonateAdmin
+	}
+
+	token, expiresIn, err := s.tokenService.IssueImpersonationToken(&user, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.activityService.LogActorEvent(user.ID, actorID, "impersonation_started", client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+	return &ImpersonationResult{Token: token, ExpiresIn: expiresIn, User: &user}, nil
+}
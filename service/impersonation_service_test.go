@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newImpersonationService(env *testEnv) *ImpersonationService {
+	return NewImpersonationService(env.users, env.roles, env.tokens, env.activities)
+}
+
+func TestImpersonationIssuesActorToken(t *testing.T) {
+	env := newTestEnv(t)
+	override(t, &config.AppConfig.Auth.ImpersonationTTL, 10*time.Minute)
+	impersonation := newImpersonationService(env)
+	admin := env.createAdmin(t, "support@example.com")
+	target := env.createUser(t, "customer@example.com")
+
+	result, err := impersonation.Start(admin.ID, target.ID, ClientInfo{IP: "203.0.113.7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExpiresIn != int64((10 * time.Minute).Seconds()) {
+		t.Fatalf("ExpiresIn = %d, want the impersonation TTL", result.ExpiresIn)
+	}
+
+	claims, err := env.tokens.ParseAccessToken(result.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != target.ID || claims.Subject != target.ID {
+		t.Fatalf("token user = %s/%s, want %s", claims.UserID, claims.Subject, target.ID)
+	}
+	if claims.Actor == nil || claims.Actor.Subject != admin.ID {
+		t.Fatalf("act = %+v, want %s", claims.Actor, admin.ID)
+	}
+	// Impersonation oturum açmaz; refresh token ve sid yoktur.
+	if claims.SessionID != "" {
+		t.Fatalf("sid = %q, want none", claims.SessionID)
+	}
+	if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl != 10*time.Minute {
+		t.Fatalf("token lifetime = %v, want 10m", ttl)
+	}
+
+	var activity entity.Activity
+	if err := env.db.Where("action = ?", "impersonation_started").First(&activity).Error; err != nil {
+		t.Fatal(err)
+	}
+	if activity.UserID == nil || *activity.UserID != target.ID || activity.ActorID == nil || *activity.ActorID != admin.ID {
+		t.Fatalf("activity = %+v, want user %s and actor %s", activity, target.ID, admin.ID)
+	}
+}
+
+func TestImpersonationRejectsSelfAdminsAndUnknownUsers(t *testing.T) {
+	env := newTestEnv(t)
+	impersonation := newImpersonationService(env)
+	admin := env.createAdmin(t, "support@example.com")
+	otherAdmin := env.createAdmin(t, "other-admin@example.com")
+
+	tests := []struct {
+		name     string
+		targetID string
+		want     error
+	}{
+		{"self", admin.ID, ErrImpersonateSelf},
+		{"admin", otherAdmin.ID, ErrImpersonateAdmin},
+		{"unknown", "00000000-0000-0000-0000-000000000000", gorm.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := impersonation.Start(admin.ID, tt.targetID, ClientInfo{}); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	var count int64
+	if err := env.db.Model(&entity.Activity{}).Where("action = ?", "impersonation_started").Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("impersonation_started activities = %d, want 0", count)
+	}
+}
+
+func TestImpersonationTokenRevokedWithTargetTokens(t *testing.T) {
+	env := newTestEnv(t)
+	impersonation := newImpersonationService(env)
+	admin := env.createAdmin(t, "support@example.com")
+	target := env.createUser(t, "customer@example.com")
+
+	result, err := impersonation.Start(admin.ID, target.ID, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := env.tokens.ParseAccessToken(result.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.tokens.RevokeUserTokens(target.ID); err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := env.tokens.IsAccessTokenRevoked(claims.ID, claims.SessionID, claims.UserID, claims.IssuedAt.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Fatal("impersonation token survived RevokeUserTokens")
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/mailer"
+	"go-initial-project/repository"
+	"go-initial-project/tenant"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidInvitation       = errors.New("invalid or expired invitation")
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to a different e-mail address")
+	ErrInvitationNotPending    = errors.New("invitation was already accepted or revoked")
+	ErrAlreadyInvited          = errors.New("an invitation is already pending for this e-mail address, resend it instead")
+	ErrAlreadyMember           = errors.New("user is already a member of this organization")
+)
+
+type InvitationService struct {
+	invitations         *repository.InvitationRepository
+	organizationService *OrganizationService
+	userService         *UserService
+	activityService     *ActivityService
+	mailer              mailer.Mailer
+}
+
+func NewInvitationService(
+	invitations *repository.InvitationRepository,
+	organizationService *OrganizationService,
+	userService *UserService,
+	activityService *ActivityService,
+	mail mailer.Mailer,
+) *InvitationService {
+	return &InvitationService{
+		invitations:         invitations,
+		organizationService: organizationService,
+		userService:         userService,
+		activityService:     activityService,
+		mailer:              mail,
+	}
+}
+
+// Create aktif organizasyona email'i role ile davet eder ve davet linkini gönderir.
+// Owner daveti sadece owner'lar gönderebilir. E-posta gönderilemezse davet silinir.
+func (s *InvitationService) Create(ctx context.Context, inviter *entity.User, inviterRole, email, role string, client ClientInfo) (*entity.Invitation, error) {
+	if role == entity.OrgRoleOwner && inviterRole != entity.OrgRoleOwner {
+		return nil, ErrOrgOwnerRequired
+	}
+	email = strings.ToLower(email)
+	if err := s.ensureNotMember(ctx, email); err != nil {
+		return nil, err
+	}
+	org, err := s.organizationService.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	invitation := &entity.Invitation{
+		Email:       email,
+		Role:        role,
+		TokenHash:   hashToken(rawToken),
+		InvitedByID: inviter.ID,
+		ExpiresAt:   now.Add(config.AppConfig.Auth.InvitationTTL),
+		SentAt:      now,
+	}
+	if err := s.invitations.WithContext(ctx).Create(invitation); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAlreadyInvited
+		}
+		return nil, err
+	}
+
+	if err := s.send(invitation, org, inviter, rawToken); err != nil {
+		if err := s.invitations.WithContext(ctx).Delete(invitation.ID, entity.Invitation{}); err != nil {
+			log.Println("❌ Invitation cleanup err:", err)
+		}
+		return nil, err
+	}
+
+	s.logEvent(inviter.ID, "invitation_created", client)
+	return invitation, nil
+}
+
+// List aktif organizasyonun tüm davetlerini döner.
+func (s *InvitationService) List(ctx context.Context) ([]entity.Invitation, error) {
+	return s.invitations.ListInvitations(ctx)
+}
+
+// Resend bekleyen davetin token'ını yeniler, süresini baştan başlatır ve linki
+// tekrar gönderir. Süresi dolmuş davetler de bu yolla canlandırılabilir.
+func (s *InvitationService) Resend(ctx context.Context, actor *entity.User, id string, client ClientInfo) (*entity.Invitation, error) {
+	invitation, err := s.invitations.FindByIDCtx(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	org, err := s.organizationService.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	invitation.TokenHash = hashToken(rawToken)
+	invitation.ExpiresAt = now.Add(config.AppConfig.Auth.InvitationTTL)
+	invitation.SentAt = now
+	rotated, err := s.invitations.Rotate(ctx, invitation.ID, invitation.TokenHash, invitation.ExpiresAt, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, ErrInvitationNotPending
+	}
+
+	if err := s.send(&invitation, org, actor, rawToken); err != nil {
+		return nil, err
+	}
+
+	s.logEvent(actor.ID, "invitation_resent", client)
+	return &invitation, nil
+}
+
+// Revoke bekleyen daveti iptal eder; link artık kabul edilmez.
+func (s *InvitationService) Revoke(ctx context.Context, actorID, id string, client ClientInfo) error {
+	if _, err := s.invitations.FindByIDCtx(ctx, id); err != nil {
+		return err
+	}
+	revoked, err := s.invitations.Revoke(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInvitationNotPending
+	}
+
+	s.logEvent(actorID, "invitation_revoked", client)
+	return nil
+}
+
+// Pending token'a ait bekleyen daveti organizasyonuyla birlikte döner.
+// Token'ın tenant'ı henüz bilinmediğinden arama tenant filtresi dışında yapılır.
+func (s *InvitationService) Pending(ctx context.Context, rawToken string) (*entity.Invitation, error) {
+	invitation, err := s.invitations.FindPending(tenant.Unscoped(ctx, "accept invitation"), hashToken(rawToken), time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidInvitation
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Organizasyon silinmişse davet de geçersizdir.
+	if invitation.Organization == nil {
+		return nil, ErrInvalidInvitation
+	}
+	return invitation, nil
+}
+
+// Accept Pending ile bulunan daveti user adına kabul eder ve user'ı davetteki
+// rolle organizasyona ekler. Davet user'ın e-posta adresine gönderilmiş olmalıdır.
+func (s *InvitationService) Accept(ctx context.Context, user *entity.User, invitation *entity.Invitation, client ClientInfo) error {
+	if !invitation.SentTo(user.Email) {
+		return ErrInvitationEmailMismatch
+	}
+
+	ctx = tenant.NewContext(ctx, invitation.OrganizationID)
+	if err := s.invitations.Accept(ctx, invitation, user.ID, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return ErrInvalidInvitation
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return ErrAlreadyMember
+		}
+		return err
+	}
+
+	s.logEvent(user.ID, "invitation_accepted", client)
+	return nil
+}
+
+// Register davetle kayıt olan kullanıcıyı oluşturur ve daveti kabul eder. İkisi
+// tek transaction'dadır: davet kabul edilemezse kullanıcı da oluşturulmaz.
+func (s *InvitationService) Register(ctx context.Context, user entity.User, invitation *entity.Invitation, client ClientInfo) (*entity.User, error) {
+	if !invitation.SentTo(user.Email) {
+		return nil, ErrInvitationEmailMismatch
+	}
+	roles, err := s.userService.DefaultRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = tenant.NewContext(ctx, invitation.OrganizationID)
+	if err := s.invitations.Register(ctx, invitation, &user, roles, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+
+	s.logEvent(user.ID, "invitation_accepted", client)
+	return &user, nil
+}
+
+// ensureNotMember email'e sahip bir kullanıcı aktif organizasyonda zaten üyeyse ErrAlreadyMember döner.
+func (s *InvitationService) ensureNotMember(ctx context.Context, email string) error {
+	user, err := s.userService.FindByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.organizationService.Membership(ctx, user.ID)
+	switch {
+	case err == nil:
+		return ErrAlreadyMember
+	case errors.Is(err, ErrNotMember):
+		return nil
+	}
+	return err
+}
+
+func (s *InvitationService) send(invitation *entity.Invitation, org *entity.Organization, inviter *entity.User, rawToken string) error {
+	link := fmt.Sprintf("%s/accept-invitation?token=%s", config.AppConfig.App.FrontendURL, url.QueryEscape(rawToken))
+	return s.mailer.Send(mailer.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You have been invited to %s", org.Name),
+		Body: fmt.Sprintf(
+			"Hi,\n\n%s %s invited you to join %s as %s. Use the link below to accept; it expires in %s.\n\n%s\n\nIf you do not have an account yet, you can create one with this e-mail address from the same link.\n",
+			inviter.FirstName, inviter.LastName, org.Name, invitation.Role, config.AppConfig.Auth.InvitationTTL, link,
+		),
+	})
+}
+
+func (s *InvitationService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
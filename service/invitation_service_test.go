@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-initial-project/entity"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestRegisterWithInvitationJoinsOrganization(t *testing.T) {
+	env := newTestEnv(t)
+	orgs := newOrganizationService(env)
+	mail := &recordingMailer{}
+	invitations := newInvitationService(env, orgs, mail)
+	owner := env.createUser(t, "owner@example.com")
+	ctx := createOrganization(t, orgs, owner)
+
+	if _, err := invitations.Create(ctx, owner, entity.OrgRoleOwner, "new@example.com", entity.OrgRoleAdmin, ClientInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	invitation, err := invitations.Pending(context.Background(), mail.lastToken(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := invitations.Register(context.Background(), entity.User{FirstName: "New", LastName: "User", Email: "new@example.com"}, invitation, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	membership, err := orgs.Membership(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if membership.Role != entity.OrgRoleAdmin {
+		t.Fatalf("role = %s, want %s", membership.Role, entity.OrgRoleAdmin)
+	}
+	roles, err := env.roles.RoleNamesForUser(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 1 || roles[0] != entity.RoleUser {
+		t.Fatalf("roles = %v, want [%s]", roles, entity.RoleUser)
+	}
+}
+
+func TestRegisterWithRevokedInvitationCreatesNoUser(t *testing.T) {
+	env := newTestEnv(t)
+	orgs := newOrganizationService(env)
+	mail := &recordingMailer{}
+	invitations := newInvitationService(env, orgs, mail)
+	owner := env.createUser(t, "owner@example.com")
+	ctx := createOrganization(t, orgs, owner)
+
+	created, err := invitations.Create(ctx, owner, entity.OrgRoleOwner, "late@example.com", entity.OrgRoleMember, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invitation, err := invitations.Pending(context.Background(), mail.lastToken(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Davet, kayıt formu gönderilmeden önce iptal edilir.
+	if err := invitations.Revoke(ctx, owner.ID, created.ID, ClientInfo{}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = invitations.Register(context.Background(), entity.User{FirstName: "Late", LastName: "User", Email: "late@example.com"}, invitation, ClientInfo{})
+	if !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("err = %v, want ErrInvalidInvitation", err)
+	}
+	if _, err := env.users.FindByEmail("late@example.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("user was created without a membership: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/repository"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	ipThrottlePrefix    = "ip:"
+	emailThrottlePrefix = "email:"
+	mfaThrottlePrefix   = "mfa:"
+	magicThrottlePrefix = "magic:"
+
+	// progressiveDelayAfter bu kadar hatadan sonra her denemede bekleme süresi ikiye katlanır.
+	progressiveDelayAfter = 3
+)
+
+var ErrLoginThrottled = errors.New("too many failed login attempts, try again later")
+
+// ThrottledError denemenin ne kadar sonra tekrar yapılabileceğini taşır.
+// errors.Is(err, Reason) ile yakalanabilir; Reason boşsa ErrLoginThrottled'dır.
+type ThrottledError struct {
+	RetryAfter time.Duration
+	Reason     error
+}
+
+func (e *ThrottledError) Error() string {
+	return e.reason().Error()
+}
+
+func (e *ThrottledError) Is(target error) bool {
+	return target == e.reason()
+}
+
+func (e *ThrottledError) reason() error {
+	if e.Reason != nil {
+		return e.Reason
+	}
+	return ErrLoginThrottled
+}
+
+func EmailThrottleKey(email string) string {
+	return emailThrottlePrefix + email
+}
+
+func IPThrottleKey(ip string) string {
+	return ipThrottlePrefix + ip
+}
+
+func MFAThrottleKey(userID string) string {
+	return mfaThrottlePrefix + userID
+}
+
+// MagicLinkThrottleKey magic link gönderim sayacı; key EmailThrottleKey veya
+// IPThrottleKey'dir. Login sayaçlarından ayrı tutulur.
+func MagicLinkThrottleKey(key string) string {
+	return magicThrottlePrefix + key
+}
+
+// LoginThrottleService başarısız giriş denemelerini e-posta, IP ve MFA
+// bazında sayar; kademeli bekleme ve geçici kilitleme uygular.
+type LoginThrottleService struct {
+	repo            *repository.LoginThrottleRepository
+	userService     *UserService
+	activityService *ActivityService
+}
+
+func NewLoginThrottleService(
+	repo *repository.LoginThrottleRepository,
+	userService *UserService,
+	activityService *ActivityService,
+) *LoginThrottleService {
+	return &LoginThrottleService{
+		repo:            repo,
+		userService:     userService,
+		activityService: activityService,
+	}
+}
+
+// Check anahtarlardan biri kilitli veya bekleme süresindeyse ne kadar
+// beklenmesi gerektiğini içeren *ThrottledError döner.
+func (s *LoginThrottleService) Check(keys ...string) error {
+	items, err := s.repo.FindByKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for _, item := range items {
+		if item.LockedUntil != nil && item.LockedUntil.After(now) {
+			wait = max(wait, item.LockedUntil.Sub(now))
+			continue
+		}
+		if now.Sub(item.LastFailedAt) > config.AppConfig.Auth.FailureWindow {
+			continue
+		}
+		if until := item.LastFailedAt.Add(delayFor(item.Key, item.Failures)); until.After(now) {
+			wait = max(wait, until.Sub(now))
+		}
+	}
+	if wait > 0 {
+		return &ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordFailure her anahtarın sayacını artırır. Eşiği geçen anahtar kilitlenir
+// ve olay activity olarak kaydedilir.
+func (s *LoginThrottleService) RecordFailure(client ClientInfo, keys ...string) error {
+	now := time.Now()
+	windowStart := now.Add(-config.AppConfig.Auth.FailureWindow)
+
+	for _, key := range keys {
+		item, err := s.repo.Increment(key, now, windowStart)
+		if err != nil {
+			return err
+		}
+		if item.Failures < thresholdFor(key) {
+			continue
+		}
+		if item.LockedUntil != nil && item.LockedUntil.After(now) {
+			continue
+		}
+		if err := s.repo.Lock(key, now.Add(config.AppConfig.Auth.LockoutDuration)); err != nil {
+			return err
+		}
+
+		action := "login_lockout"
+		if strings.HasPrefix(key, ipThrottlePrefix) {
+			action = "login_lockout_ip"
+		}
+		if err := s.activityService.LogEvent(s.userIDFor(key), action, client); err != nil {
+			log.Printf("⚠️ failed to log %s: %v", action, err)
+		}
+	}
+	return nil
+}
+
+// userIDFor kilitlenen anahtarın ait olduğu kullanıcıyı bulur. IP anahtarları
+// ve kayıtlı olmayan e-postalar için boş döner.
+func (s *LoginThrottleService) userIDFor(key string) string {
+	switch {
+	case strings.HasPrefix(key, mfaThrottlePrefix):
+		return strings.TrimPrefix(key, mfaThrottlePrefix)
+	case strings.HasPrefix(key, emailThrottlePrefix):
+		user, err := s.userService.FindByEmail(strings.TrimPrefix(key, emailThrottlePrefix))
+		if err == nil {
+			return user.ID
+		}
+	}
+	return ""
+}
+
+// Allow başarısız denemeleri değil her isteği sayar: key için window içinde
+// limit'ten fazla istek gelirse reason ile *ThrottledError döner.
+func (s *LoginThrottleService) Allow(key string, limit int, window time.Duration, reason error) error {
+	now := time.Now()
+	item, err := s.repo.Increment(key, now, now.Add(-window))
+	if err != nil {
+		return err
+	}
+	if item.Failures > limit {
+		return &ThrottledError{RetryAfter: window, Reason: reason}
+	}
+	return nil
+}
+
+// Reset başarılı girişten sonra sayaçları sıfırlar. IP sayacı burada
+// sıfırlanmamalı; aksi halde saldırgan kendi hesabıyla giriş yaparak IP
+// limitini aşabilir.
+func (s *LoginThrottleService) Reset(keys ...string) error {
+	return s.repo.DeleteKeys(keys)
+}
+
+func thresholdFor(key string) int {
+	if strings.HasPrefix(key, ipThrottlePrefix) {
+		return config.AppConfig.Auth.IPLockoutThreshold
+	}
+	return config.AppConfig.Auth.LockoutThreshold
+}
+
+// delayFor kademeli bekleme süresi: 3. hatadan sonra 1s, 2s, 4s ... en fazla
+// ProgressiveDelayMax. Aynı IP'yi paylaşan kullanıcıları etkilememek için IP
+// anahtarlarında sadece kilitleme uygulanır.
+func delayFor(key string, failures int) time.Duration {
+	if strings.HasPrefix(key, ipThrottlePrefix) || failures < progressiveDelayAfter {
+		return 0
+	}
+	shift := min(failures-progressiveDelayAfter, 16)
+	return min(time.Second<<shift, config.AppConfig.Auth.ProgressiveDelayMax)
+}
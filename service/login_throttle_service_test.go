@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"testing"
+	"time"
+)
+
+func newLoginThrottleService(env *testEnv) *LoginThrottleService {
+	return NewLoginThrottleService(repository.NewLoginThrottleRepository(env.db), env.users, env.activities)
+}
+
+func recordFailures(t *testing.T, throttle *LoginThrottleService, n int, keys ...string) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := throttle.RecordFailure(ClientInfo{IP: "203.0.113.7"}, keys...); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func countActivities(t *testing.T, env *testEnv, action string) int64 {
+	t.Helper()
+	var count int64
+	if err := env.db.Model(&entity.Activity{}).Where("action = ?", action).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestDelayFor(t *testing.T) {
+	override(t, &config.AppConfig.Auth.ProgressiveDelayMax, 30*time.Second)
+
+	tests := []struct {
+		key      string
+		failures int
+		want     time.Duration
+	}{
+		{EmailThrottleKey("a@example.com"), 1, 0},
+		{EmailThrottleKey("a@example.com"), 2, 0},
+		{EmailThrottleKey("a@example.com"), 3, time.Second},
+		{EmailThrottleKey("a@example.com"), 4, 2 * time.Second},
+		{EmailThrottleKey("a@example.com"), 5, 4 * time.Second},
+		{EmailThrottleKey("a@example.com"), 9, 30 * time.Second},
+		{EmailThrottleKey("a@example.com"), 100, 30 * time.Second},
+		{MFAThrottleKey("user"), 3, time.Second},
+		{IPThrottleKey("203.0.113.7"), 3, 0},
+		{IPThrottleKey("203.0.113.7"), 100, 0},
+	}
+	for _, tt := range tests {
+		if got := delayFor(tt.key, tt.failures); got != tt.want {
+			t.Errorf("delayFor(%q, %d) = %v, want %v", tt.key, tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAppliesProgressiveDelay(t *testing.T) {
+	env := newTestEnv(t)
+	throttle := newLoginThrottleService(env)
+	key := EmailThrottleKey("delay@example.com")
+
+	recordFailures(t, throttle, progressiveDelayAfter-1, key)
+	if err := throttle.Check(key); err != nil {
+		t.Fatalf("err = %v before the delay threshold", err)
+	}
+
+	recordFailures(t, throttle, 1, key)
+	var throttled *ThrottledError
+	if err := throttle.Check(key); !errors.As(err, &throttled) {
+		t.Fatalf("err = %v, want *ThrottledError", err)
+	}
+	if throttled.RetryAfter <= 0 || throttled.RetryAfter > time.Second {
+		t.Fatalf("RetryAfter = %v, want up to 1s", throttled.RetryAfter)
+	}
+	if !errors.Is(throttled, ErrLoginThrottled) {
+		t.Fatal("ThrottledError does not match ErrLoginThrottled")
+	}
+}
+
+func TestLockoutThreshold(t *testing.T) {
+	env := newTestEnv(t)
+	throttle := newLoginThrottleService(env)
+	// Kademeli bekleme kapalı; sadece kilitleme test edilir.
+	override(t, &config.AppConfig.Auth.ProgressiveDelayMax, 0)
+	override(t, &config.AppConfig.Auth.LockoutThreshold, 5)
+	override(t, &config.AppConfig.Auth.LockoutDuration, time.Hour)
+
+	user := env.createUser(t, "lockout@example.com")
+	key := EmailThrottleKey(user.Email)
+
+	recordFailures(t, throttle, 4, key)
+	if err := throttle.Check(key); err != nil {
+		t.Fatalf("err = %v below the lockout threshold", err)
+	}
+
+	recordFailures(t, throttle, 1, key)
+	var throttled *ThrottledError
+	if err := throttle.Check(key); !errors.As(err, &throttled) {
+		t.Fatalf("err = %v, want *ThrottledError", err)
+	}
+	if throttled.RetryAfter <= 59*time.Minute {
+		t.Fatalf("RetryAfter = %v, want the lockout duration", throttled.RetryAfter)
+	}
+
+	// Kilitliyken gelen hatalar kilidi uzatmaz ve tekrar loglanmaz.
+	recordFailures(t, throttle, 3, key)
+	var activities []entity.Activity
+	if err := env.db.Where("action = ?", "login_lockout").Find(&activities).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 1 || activities[0].UserID == nil || *activities[0].UserID != user.ID {
+		t.Fatalf("lockout activities = %+v, want one for %s", activities, user.ID)
+	}
+}
+
+func TestIPLockoutThreshold(t *testing.T) {
+	env := newTestEnv(t)
+	throttle := newLoginThrottleService(env)
+	override(t, &config.AppConfig.Auth.LockoutThreshold, 100)
+	override(t, &config.AppConfig.Auth.IPLockoutThreshold, 3)
+
+	ipKey := IPThrottleKey("203.0.113.7")
+	// Aynı IP'den farklı e-postalara yapılan denemeler IP sayacında birikir.
+	for i, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if err := throttle.Check(ipKey); err != nil {
+			t.Fatalf("attempt %d: err = %v", i, err)
+		}
+		recordFailures(t, throttle, 1, EmailThrottleKey(email), ipKey)
+	}
+
+	if err := throttle.Check(ipKey); !errors.Is(err, ErrLoginThrottled) {
+		t.Fatalf("err = %v, want ErrLoginThrottled", err)
+	}
+	if err := throttle.Check(EmailThrottleKey("d@example.com")); err != nil {
+		t.Fatalf("err = %v, e-mail key must not be locked", err)
+	}
+	if count := countActivities(t, env, "login_lockout_ip"); count != 1 {
+		t.Fatalf("login_lockout_ip activities = %d, want 1", count)
+	}
+}
+
+func TestResetKeepsIPCounter(t *testing.T) {
+	env := newTestEnv(t)
+	throttle := newLoginThrottleService(env)
+	emailKey := EmailThrottleKey("reset@example.com")
+	ipKey := IPThrottleKey("203.0.113.7")
+
+	recordFailures(t, throttle, 2, emailKey, ipKey)
+	// Başarılı login sadece e-posta sayacını sıfırlar.
+	if err := throttle.Reset(emailKey); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := throttle.repo.FindByKeys([]string{emailKey, ipKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Key != ipKey || items[0].Failures != 2 {
+		t.Fatalf("throttle rows = %+v, want only the IP counter with 2 failures", items)
+	}
+}
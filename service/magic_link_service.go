@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/mailer"
+	"log"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrMagicLinkThrottled = errors.New("too many sign-in links requested, try again later")
+
+type MagicLinkService struct {
+	userService     *UserService
+	tokenService    *TokenService
+	throttle        *LoginThrottleService
+	activityService *ActivityService
+	mailer          mailer.Mailer
+}
+
+func NewMagicLinkService(
+	userService *UserService,
+	tokenService *TokenService,
+	throttle *LoginThrottleService,
+	activityService *ActivityService,
+	mail mailer.Mailer,
+) *MagicLinkService {
+	return &MagicLinkService{
+		userService:     userService,
+		tokenService:    tokenService,
+		throttle:        throttle,
+		activityService: activityService,
+		mailer:          mail,
+	}
+}
+
+// Send e-postaya tek kullanımlık giriş linki gönderir. Limit kayıtlı olmayan
+// adresler için de sayılır ve hata dönmez; böylece endpoint hesap varlığını sızdırmaz.
+func (s *MagicLinkService) Send(email string, client ClientInfo) error {
+	cfg := config.AppConfig.Auth
+	if err := s.throttle.Allow(MagicLinkThrottleKey(IPThrottleKey(client.IP)), cfg.MagicLinkIPLimit, cfg.MagicLinkWindow, ErrMagicLinkThrottled); err != nil {
+		s.logThrottled(err, client)
+		return err
+	}
+	if err := s.throttle.Allow(MagicLinkThrottleKey(EmailThrottleKey(email)), cfg.MagicLinkEmailLimit, cfg.MagicLinkWindow, ErrMagicLinkThrottled); err != nil {
+		s.logThrottled(err, client)
+		return err
+	}
+
+	user, err := s.userService.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logEvent("", "magic_link_requested", client)
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.tokenService.SignPurposeToken(PurposeMagicLink, user.ID, user.Email, cfg.MagicLinkTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/magic-link?token=%s", config.AppConfig.App.FrontendURL, url.QueryEscape(token))
+	if err := s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to sign in. It expires in %s and can only be used once.\n\n%s\n\nIf you did not ask for this, you can ignore this e-mail.\n",
+			user.FirstName, cfg.MagicLinkTTL, link,
+		),
+	}); err != nil {
+		return err
+	}
+
+	s.logEvent(user.ID, "magic_link_requested", client)
+	return nil
+}
+
+// Exchange linkteki token'ı tüketir ve kullanıcıyı döner. Link e-postaya
+// erişimi kanıtladığı için adres doğrulanmamışsa doğrulanmış işaretlenir.
+// Token üretildikten sonra e-posta değiştiyse link geçersiz sayılır.
+func (s *MagicLinkService) Exchange(token string, client ClientInfo) (*entity.User, error) {
+	claims, err := s.tokenService.ConsumePurposeToken(PurposeMagicLink, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.First(map[string]interface{}{"id": claims.Subject})
+	if err != nil || user.Email != claims.Email {
+		return nil, ErrInvalidPurposeToken
+	}
+
+	if !user.EmailVerified() {
+		now := time.Now()
+		if err := s.userService.UpdateWhere(
+			map[string]interface{}{"id": user.ID},
+			map[string]interface{}{"email_verified_at": now},
+		); err != nil {
+			return nil, err
+		}
+		user.EmailVerifiedAt = &now
+	}
+
+	s.logEvent(user.ID, "magic_link_redeemed", client)
+	return &user, nil
+}
+
+// logThrottled limite takılan istekleri de kaydeder; kullanıcıya bakılmaz.
+func (s *MagicLinkService) logThrottled(err error, client ClientInfo) {
+	if errors.Is(err, ErrMagicLinkThrottled) {
+		s.logEvent("", "magic_link_throttled", client)
+	}
+}
+
+func (s *MagicLinkService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
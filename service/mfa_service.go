@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/encryption"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"image/png"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	totpPeriod        = 30
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled    = errors.New("two-factor authentication is not set up")
+	ErrInvalidMFACode    = errors.New("invalid authentication code")
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    totpPeriod,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// TOTPEnrollment kullanıcının authenticator uygulamasına eklemesi gereken bilgiler.
+type TOTPEnrollment struct {
+	Secret string
+	URL    string
+	QRPNG  []byte
+}
+
+type MFAService struct {
+	userService     *UserService
+	tokenService    *TokenService
+	activityService *ActivityService
+	throttle        *LoginThrottleService
+	recoveryCodes   *repository.RecoveryCodeRepository
+	cipher          *encryption.Cipher
+	webauthn        *WebAuthnService
+}
+
+func NewMFAService(
+	userService *UserService,
+	tokenService *TokenService,
+	activityService *ActivityService,
+	throttle *LoginThrottleService,
+	recoveryCodes *repository.RecoveryCodeRepository,
+	cipher *encryption.Cipher,
+	webauthn *WebAuthnService,
+) *MFAService {
+	return &MFAService{
+		userService:     userService,
+		tokenService:    tokenService,
+		activityService: activityService,
+		throttle:        throttle,
+		recoveryCodes:   recoveryCodes,
+		cipher:          cipher,
+		webauthn:        webauthn,
+	}
+}
+
+// EnrollTOTP yeni bir secret üretip şifreli olarak kaydeder. Confirm edilene
+// kadar login akışı etkilenmez; tekrar çağrılırsa secret yenilenir.
+func (s *MFAService) EnrollTOTP(user *entity.User) (*TOTPEnrollment, error) {
+	if user.MFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      config.AppConfig.App.Name,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.cipher.Encrypt(key.Secret())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.updateUser(user.ID, map[string]interface{}{
+		"totp_secret":     encrypted,
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	}); err != nil {
+		return nil, err
+	}
+
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{Secret: key.Secret(), URL: key.URL(), QRPNG: buf.Bytes()}, nil
+}
+
+// ConfirmTOTP ilk kodu doğrular, MFA'yı aktif eder ve recovery code'ları döner.
+func (s *MFAService) ConfirmTOTP(user *entity.User, code string, client ClientInfo) ([]string, error) {
+	if user.MFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+	if err := s.verifyThrottled(user, client, func() (bool, error) { return s.validateTOTP(user, code) }); err != nil {
+		return nil, err
+	}
+
+	if err := s.updateUser(user.ID, map[string]interface{}{"totp_enabled_at": time.Now()}); err != nil {
+		return nil, err
+	}
+	codes, err := s.RegenerateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logEvent(user.ID, "mfa_enabled", client)
+	return codes, nil
+}
+
+// DisableTOTP geçerli bir kod (veya recovery code) ile MFA'yı kapatır.
+func (s *MFAService) DisableTOTP(user *entity.User, code string, client ClientInfo) error {
+	if !user.MFAEnabled() {
+		return ErrMFANotEnrolled
+	}
+	if err := s.verifyThrottled(user, client, func() (bool, error) { return s.Verify(user, code) }); err != nil {
+		return err
+	}
+
+	if err := s.updateUser(user.ID, map[string]interface{}{
+		"totp_secret":     "",
+		"totp_enabled_at": nil,
+		"totp_last_step":  0,
+	}); err != nil {
+		return err
+	}
+	if err := s.recoveryCodes.DeleteForUser(user.ID); err != nil {
+		return err
+	}
+
+	s.logEvent(user.ID, "mfa_disabled", client)
+	return nil
+}
+
+// ResetRecoveryCodes geçerli bir TOTP veya recovery code ile kodları yeniler.
+func (s *MFAService) ResetRecoveryCodes(user *entity.User, code string, client ClientInfo) ([]string, error) {
+	if err := s.verifyThrottled(user, client, func() (bool, error) { return s.Verify(user, code) }); err != nil {
+		return nil, err
+	}
+	return s.RegenerateRecoveryCodes(user.ID)
+}
+
+// RegenerateRecoveryCodes eski kodları geçersiz kılıp yenilerini üretir.
+// Kodlar sadece bu çağrının sonucunda bir kez gösterilir.
+func (s *MFAService) RegenerateRecoveryCodes(userID string) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashToken(code)
+	}
+	if err := s.recoveryCodes.ReplaceForUser(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Verify 6 haneli TOTP kodunu ya da tek kullanımlık recovery code'u doğrular.
+func (s *MFAService) Verify(user *entity.User, code string) (bool, error) {
+	if !user.MFAEnabled() {
+		return false, ErrMFANotEnrolled
+	}
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) == int(otp.DigitsSix) {
+		return s.validateTOTP(user, code)
+	}
+	return s.recoveryCodes.Consume(user.ID, hashToken(strings.ToLower(code)), time.Now())
+}
+
+// IssueChallenge şifresi doğrulanmış ama MFA adımı bekleyen kullanıcı için
+// kısa ömürlü, tek kullanımlık bir challenge token üretir.
+func (s *MFAService) IssueChallenge(user *entity.User) (string, error) {
+	return s.tokenService.SignPurposeToken(PurposeMFAChallenge, user.ID, user.Email, config.AppConfig.Auth.MFAChallengeTTL)
+}
+
+// Methods ikinci adımda kullanılabilecek yöntemleri döner: TOTP (ve recovery
+// code) ile kullanıcının kayıtlı passkey'i varsa "webauthn".
+func (s *MFAService) Methods(user *entity.User) ([]string, error) {
+	methods := []string{"totp"}
+	hasPasskey, err := s.webauthn.HasCredentials(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if hasPasskey {
+		methods = append(methods, "webauthn")
+	}
+	return methods, nil
+}
+
+// CompleteChallenge challenge token + kod ile login'in ikinci adımını tamamlar.
+// Yanlış kodlar şifre denemeleri gibi sayılır; limit aşılırsa *ThrottledError döner.
+func (s *MFAService) CompleteChallenge(challenge, code string, client ClientInfo) (*entity.User, error) {
+	return s.completeChallenge(challenge, client, func(user *entity.User) (bool, error) {
+		return s.Verify(user, code)
+	})
+}
+
+// BeginWebAuthnChallenge ikinci adımı passkey ile tamamlamak için assertion
+// seçeneklerini üretir. Challenge token burada tüketilmez.
+func (s *MFAService) BeginWebAuthnChallenge(challenge string) (*protocol.CredentialAssertion, string, error) {
+	claims, err := s.tokenService.ParsePurposeToken(PurposeMFAChallenge, challenge)
+	if err != nil {
+		return nil, "", err
+	}
+	user, err := s.userService.First(map[string]interface{}{"id": claims.Subject})
+	if err != nil {
+		return nil, "", ErrInvalidPurposeToken
+	}
+	return s.webauthn.BeginSecondFactor(&user)
+}
+
+// CompleteWebAuthnChallenge ikinci adımı passkey assertion'ı ile tamamlar;
+// başarısız denemeler TOTP kodları gibi sayılır.
+func (s *MFAService) CompleteWebAuthnChallenge(challenge, session string, response []byte, client ClientInfo) (*entity.User, error) {
+	return s.completeChallenge(challenge, client, func(user *entity.User) (bool, error) {
+		return s.webauthn.VerifySecondFactor(user, session, response, client)
+	})
+}
+
+func (s *MFAService) completeChallenge(challenge string, client ClientInfo, verify func(user *entity.User) (bool, error)) (*entity.User, error) {
+	claims, err := s.tokenService.ParsePurposeToken(PurposeMFAChallenge, challenge)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userService.First(map[string]interface{}{"id": claims.Subject})
+	if err != nil {
+		return nil, ErrInvalidPurposeToken
+	}
+	if err := s.throttle.Check(MFAThrottleKey(user.ID), IPThrottleKey(client.IP)); err != nil {
+		return nil, err
+	}
+
+	// Challenge koddan önce tüketilir; eşzamanlı iki istek aynı challenge ile
+	// recovery code veya TOTP adımı harcayamaz. Yanlış kodda login baştan yapılır.
+	if _, err := s.tokenService.ConsumePurposeToken(PurposeMFAChallenge, challenge); err != nil {
+		return nil, err
+	}
+	if err := s.verifyThrottled(&user, client, func() (bool, error) { return verify(&user) }); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// verifyThrottled kodu kullanıcı ve IP bazlı deneme limitiyle doğrular. Yanlış
+// kodlar şifre denemeleri gibi sayılır; limit aşılırsa *ThrottledError döner.
+func (s *MFAService) verifyThrottled(user *entity.User, client ClientInfo, verify func() (bool, error)) error {
+	keys := []string{MFAThrottleKey(user.ID), IPThrottleKey(client.IP)}
+	if err := s.throttle.Check(keys...); err != nil {
+		return err
+	}
+
+	ok, err := verify()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.logEvent(user.ID, "mfa_failed", client)
+		if err := s.throttle.RecordFailure(client, keys...); err != nil {
+			log.Printf("⚠️ failed to record mfa failure: %v", err)
+		}
+		return ErrInvalidMFACode
+	}
+	if err := s.throttle.Reset(MFAThrottleKey(user.ID)); err != nil {
+		log.Printf("⚠️ failed to reset mfa throttle: %v", err)
+	}
+	return nil
+}
+
+// validateTOTP ±1 periyot toleransla kodu kontrol eder. Aynı periyottaki kod
+// ikinci kez kabul edilmez (replay).
+func (s *MFAService) validateTOTP(user *entity.User, code string) (bool, error) {
+	secret, err := s.cipher.Decrypt(user.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, skew := range []int64{-1, 0, 1} {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		expected, err := totp.GenerateCodeCustom(secret, at, totpOpts)
+		if err != nil {
+			return false, err
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+
+		step := at.Unix() / totpPeriod
+		if step <= user.TOTPLastStep {
+			return false, nil
+		}
+		return s.userService.AdvanceTOTPStep(user.ID, step)
+	}
+	return false, nil
+}
+
+func (s *MFAService) updateUser(userID string, values map[string]interface{}) error {
+	return s.userService.UpdateWhere(map[string]interface{}{"id": userID}, values)
+}
+
+func (s *MFAService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
+
+// newRecoveryCode "abcde-fghij" formatında 50 bit entropili kod üretir.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return raw[:5] + "-" + raw[5:], nil
+}
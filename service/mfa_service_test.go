@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/encryption"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+)
+
+func newMFAService(t *testing.T, env *testEnv) *MFAService {
+	t.Helper()
+	cipher, err := encryption.New([]byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	throttle := NewLoginThrottleService(repository.NewLoginThrottleRepository(env.db), env.users, env.activities)
+	return NewMFAService(env.users, env.tokens, env.activities, throttle, repository.NewRecoveryCodeRepository(env.db), cipher, newWebAuthnService(t, env))
+}
+
+// enableTOTP TOTP'yi aktif eder; güncel kullanıcıyı, secret'ı ve recovery code'ları döner.
+func enableTOTP(t *testing.T, env *testEnv, mfa *MFAService, user *entity.User) (*entity.User, string, []string) {
+	t.Helper()
+	enrollment, err := mfa.EnrollTOTP(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user = reloadUser(t, env, user.ID)
+	code, err := totp.GenerateCode(enrollment.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := mfa.ConfirmTOTP(user, code, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reloadUser(t, env, user.ID), enrollment.Secret, codes
+}
+
+func reloadUser(t *testing.T, env *testEnv, id string) *entity.User {
+	t.Helper()
+	user, err := env.users.First(map[string]interface{}{"id": id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+func TestDisableTOTPThrottled(t *testing.T) {
+	env := newTestEnv(t)
+	mfa := newMFAService(t, env)
+	user, secret, _ := enableTOTP(t, env, mfa, env.createUser(t, "disable-totp@example.com"))
+
+	for i := 0; i < progressiveDelayAfter; i++ {
+		if err := mfa.DisableTOTP(user, "000000", ClientInfo{IP: "203.0.113.7"}); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("attempt %d: err = %v, want ErrInvalidMFACode", i, err)
+		}
+	}
+
+	// Bekleme süresi dolmadan doğru kod bile denenemez.
+	code, err := totp.GenerateCode(secret, time.Now().Add(totpPeriod*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var throttled *ThrottledError
+	if err := mfa.DisableTOTP(user, code, ClientInfo{IP: "203.0.113.7"}); !errors.As(err, &throttled) {
+		t.Fatalf("err = %v, want *ThrottledError", err)
+	}
+	if !reloadUser(t, env, user.ID).MFAEnabled() {
+		t.Fatal("MFA disabled while throttled")
+	}
+}
+
+func TestResetRecoveryCodesThrottled(t *testing.T) {
+	env := newTestEnv(t)
+	mfa := newMFAService(t, env)
+	user, _, codes := enableTOTP(t, env, mfa, env.createUser(t, "recovery-reset@example.com"))
+
+	for i := 0; i < progressiveDelayAfter; i++ {
+		if _, err := mfa.ResetRecoveryCodes(user, "wrong-code", ClientInfo{}); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("attempt %d: err = %v, want ErrInvalidMFACode", i, err)
+		}
+	}
+	var throttled *ThrottledError
+	if _, err := mfa.ResetRecoveryCodes(user, codes[0], ClientInfo{}); !errors.As(err, &throttled) {
+		t.Fatalf("err = %v, want *ThrottledError", err)
+	}
+}
+
+func TestCompleteChallengeConsumesChallengeBeforeCode(t *testing.T) {
+	env := newTestEnv(t)
+	mfa := newMFAService(t, env)
+	user, _, codes := enableTOTP(t, env, mfa, env.createUser(t, "challenge-order@example.com"))
+
+	challenge, err := mfa.IssueChallenge(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mfa.CompleteChallenge(challenge, "wrong-code", ClientInfo{}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("err = %v, want ErrInvalidMFACode", err)
+	}
+
+	// Tüketilmiş challenge ile gönderilen recovery code harcanmamalı.
+	if _, err := mfa.CompleteChallenge(challenge, codes[0], ClientInfo{}); !errors.Is(err, ErrInvalidPurposeToken) {
+		t.Fatalf("err = %v, want ErrInvalidPurposeToken", err)
+	}
+
+	challenge, err = mfa.IssueChallenge(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completed, err := mfa.CompleteChallenge(challenge, codes[0], ClientInfo{})
+	if err != nil {
+		t.Fatalf("recovery code was spent by a rejected challenge: %v", err)
+	}
+	if completed.ID != user.ID {
+		t.Fatalf("completed as %s, want %s", completed.ID, user.ID)
+	}
+}
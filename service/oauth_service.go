@@ -0,0 +1,528 @@
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RFC 6749 hata kodları
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthUnauthorizedClient      = "unauthorized_client"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthAccessDenied            = "access_denied"
+)
+
+var ErrInvalidOAuthClient = errors.New("invalid client configuration")
+
+// OAuthError client'a RFC 6749'daki "error" ve "error_description" olarak döner.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func oauthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+// AuthorizationRequest /oauth/authorize parametreleri. PKCE (S256) zorunludur.
+type AuthorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// AuthorizationPrompt onay ekranında gösterilecek bilgiler.
+type AuthorizationPrompt struct {
+	Client          *entity.OAuthClient
+	Scopes          []string
+	ConsentRequired bool
+}
+
+type OAuthToken struct {
+	AccessToken string
+	ExpiresIn   int64
+	Scopes      []string
+}
+
+// Introspection RFC 7662 cevabı; Active false ise diğer alanlar boştur.
+type Introspection struct {
+	Active    bool
+	Scope     string
+	ClientID  string
+	Subject   string
+	ExpiresAt int64
+	IssuedAt  int64
+}
+
+// OAuthGrant kullanıcının onay verdiği client ve scope'lar.
+type OAuthGrant struct {
+	Client  *entity.OAuthClient
+	Consent entity.OAuthConsent
+}
+
+// OAuthService API'yi üçüncü parti uygulamalara açan OAuth2 sunucusu:
+// client kaydı, PKCE'li authorization code, client credentials, onaylar,
+// introspection (RFC 7662) ve revocation (RFC 7009).
+type OAuthService struct {
+	clients         *repository.OAuthClientRepository
+	codes           *repository.OAuthAuthorizationCodeRepository
+	consents        *repository.OAuthConsentRepository
+	userService     *UserService
+	tokenService    *TokenService
+	activityService *ActivityService
+}
+
+func NewOAuthService(
+	clients *repository.OAuthClientRepository,
+	codes *repository.OAuthAuthorizationCodeRepository,
+	consents *repository.OAuthConsentRepository,
+	userService *UserService,
+	tokenService *TokenService,
+	activityService *ActivityService,
+) *OAuthService {
+	return &OAuthService{
+		clients:         clients,
+		codes:           codes,
+		consents:        consents,
+		userService:     userService,
+		tokenService:    tokenService,
+		activityService: activityService,
+	}
+}
+
+// ---------------- CLIENTS ----------------
+
+// CreateClient yeni client kaydeder. Confidential client'ların secret'ı
+// sadece bir kez döner, public client'lar için boştur.
+func (s *OAuthService) CreateClient(name string, redirectURIs, grantTypes, scopes []string, public bool) (*entity.OAuthClient, string, error) {
+	for _, grant := range grantTypes {
+		if grant != entity.GrantAuthorizationCode && grant != entity.GrantClientCredentials {
+			return nil, "", fmt.Errorf("%w: unsupported grant type %s", ErrInvalidOAuthClient, grant)
+		}
+	}
+	if public && slices.Contains(grantTypes, entity.GrantClientCredentials) {
+		return nil, "", fmt.Errorf("%w: public clients cannot use client_credentials", ErrInvalidOAuthClient)
+	}
+	if slices.Contains(grantTypes, entity.GrantAuthorizationCode) && len(redirectURIs) == 0 {
+		return nil, "", fmt.Errorf("%w: authorization_code requires at least one redirect uri", ErrInvalidOAuthClient)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(entity.AllPermissions, scope) {
+			return nil, "", fmt.Errorf("%w: unknown scope %s", ErrInvalidOAuthClient, scope)
+		}
+	}
+
+	client := &entity.OAuthClient{
+		Name:         name,
+		Public:       public,
+		RedirectURIs: redirectURIs,
+		GrantTypes:   grantTypes,
+		Scopes:       scopes,
+	}
+	var secret string
+	if !public {
+		var err error
+		if secret, err = randomToken(); err != nil {
+			return nil, "", err
+		}
+		client.SecretHash = hashToken(secret)
+	}
+	if err := s.clients.Create(client); err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+func (s *OAuthService) ListClients() ([]entity.OAuthClient, error) {
+	return s.clients.List()
+}
+
+// DeleteClient client'ı siler ve verdiği tüm token'ları geçersiz kılar.
+func (s *OAuthService) DeleteClient(id string) (bool, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+	ok, err := s.clients.DeleteClient(id)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return true, s.tokenService.RevokeOAuthGrant(id, "")
+}
+
+// AuthenticateClient token, introspection ve revocation uçlarında client'ı
+// doğrular. Public client'lar secret göndermez.
+func (s *OAuthService) AuthenticateClient(clientID, secret string) (*entity.OAuthClient, error) {
+	client, err := s.findClient(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client.Public {
+		if secret != "" {
+			return nil, oauthError(OAuthInvalidClient, "public clients must not send a secret")
+		}
+		return client, nil
+	}
+	if secret == "" || subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashToken(secret))) != 1 {
+		return nil, oauthError(OAuthInvalidClient, "client authentication failed")
+	}
+	return client, nil
+}
+
+func (s *OAuthService) findClient(clientID string) (*entity.OAuthClient, error) {
+	if _, err := uuid.Parse(clientID); err != nil {
+		return nil, oauthError(OAuthInvalidClient, "unknown client")
+	}
+	client, err := s.clients.FindByClientID(clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, oauthError(OAuthInvalidClient, "unknown client")
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// ---------------- AUTHORIZATION CODE ----------------
+
+// PrepareAuthorization isteği doğrular ve kullanıcının daha önce aynı
+// scope'lara onay verip vermediğini söyler.
+func (s *OAuthService) PrepareAuthorization(userID string, req AuthorizationRequest) (*AuthorizationPrompt, error) {
+	client, scopes, err := s.validateAuthorization(req)
+	if err != nil {
+		return nil, err
+	}
+	consent, err := s.consents.Find(userID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthorizationPrompt{
+		Client:          client,
+		Scopes:          scopes,
+		ConsentRequired: consent == nil || !covers(consent.Scopes, scopes),
+	}, nil
+}
+
+// Authorize kullanıcının kararını işler ve client'a dönülecek redirect
+// adresini üretir: onaylandıysa ?code=...&state=..., reddedildiyse
+// ?error=access_denied&state=...
+func (s *OAuthService) Authorize(userID string, req AuthorizationRequest, approve bool, info ClientInfo) (string, error) {
+	client, scopes, err := s.validateAuthorization(req)
+	if err != nil {
+		return "", err
+	}
+	if !approve {
+		return redirectWith(req.RedirectURI, url.Values{"error": {OAuthAccessDenied}, "state": {req.State}})
+	}
+
+	consent, err := s.consents.Find(userID, client.ID)
+	if err != nil {
+		return "", err
+	}
+	if consent == nil || !covers(consent.Scopes, scopes) {
+		granted := scopes
+		if consent != nil {
+			granted = union(consent.Scopes, scopes)
+		}
+		if err := s.consents.Save(&entity.OAuthConsent{UserID: userID, ClientID: client.ID, Scopes: granted}); err != nil {
+			return "", err
+		}
+		s.logEvent(userID, "oauth_consent_granted", info)
+	}
+
+	code, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.codes.Create(&entity.OAuthAuthorizationCode{
+		CodeHash:      hashToken(code),
+		ClientID:      client.ID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     time.Now().Add(config.AppConfig.OAuth.CodeTTL),
+	}); err != nil {
+		return "", err
+	}
+	return redirectWith(req.RedirectURI, url.Values{"code": {code}, "state": {req.State}})
+}
+
+func (s *OAuthService) validateAuthorization(req AuthorizationRequest) (*entity.OAuthClient, []string, error) {
+	client, err := s.findClient(req.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !client.AllowsGrant(entity.GrantAuthorizationCode) {
+		return nil, nil, oauthError(OAuthUnauthorizedClient, "client is not allowed to use the authorization code grant")
+	}
+	if !client.AllowsRedirect(req.RedirectURI) {
+		return nil, nil, oauthError(OAuthInvalidRequest, "redirect_uri is not registered for this client")
+	}
+	if req.ResponseType != "code" {
+		return nil, nil, oauthError(OAuthUnsupportedResponseType, "only response_type=code is supported")
+	}
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != "S256" {
+		return nil, nil, oauthError(OAuthInvalidRequest, "PKCE with code_challenge_method=S256 is required")
+	}
+	scopes, err := resolveScopes(client, req.Scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, scopes, nil
+}
+
+// ExchangeCode authorization code'u access token'a çevirir. Kod; client,
+// redirect_uri ve PKCE doğrulandıktan sonra kullanılmış işaretlenir, böylece
+// başka bir client'ın denemesi kodu yakamaz. Aynı client'tan kullanılmış bir
+// kod tekrar gelirse çalınmış kabul edilir ve o koddan alınan token'lar iptal edilir.
+func (s *OAuthService) ExchangeCode(client *entity.OAuthClient, code, redirectURI, verifier string, info ClientInfo) (*OAuthToken, error) {
+	if !client.AllowsGrant(entity.GrantAuthorizationCode) {
+		return nil, oauthError(OAuthUnauthorizedClient, "client is not allowed to use the authorization code grant")
+	}
+
+	hash := hashToken(code)
+	stored, err := s.codes.FindByHash(hash)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, oauthError(OAuthInvalidGrant, "invalid or expired authorization code")
+	}
+	if stored.ClientID != client.ID {
+		return nil, oauthError(OAuthInvalidGrant, "authorization code was issued to another client or redirect_uri")
+	}
+	if stored.UsedAt != nil {
+		return nil, s.codeReused(stored, info)
+	}
+	if stored.RedirectURI != redirectURI {
+		return nil, oauthError(OAuthInvalidGrant, "authorization code was issued to another client or redirect_uri")
+	}
+	if !verifyPKCE(verifier, stored.CodeChallenge) {
+		return nil, oauthError(OAuthInvalidGrant, "invalid code_verifier")
+	}
+
+	if _, err := s.codes.Consume(hash, time.Now()); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		// Eşzamanlı bir istek kodu arada kullandıysa bu da tekrar kullanımdır.
+		if used, err := s.codes.FindByHash(hash); err == nil && used.UsedAt != nil {
+			return nil, s.codeReused(used, info)
+		}
+		return nil, oauthError(OAuthInvalidGrant, "invalid or expired authorization code")
+	}
+
+	user, err := s.userService.First(map[string]interface{}{"id": stored.UserID})
+	if err != nil {
+		return nil, oauthError(OAuthInvalidGrant, "user no longer exists")
+	}
+
+	token, expiresIn, err := s.tokenService.IssueOAuthToken(client.ID, &user, stored.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthToken{AccessToken: token, ExpiresIn: expiresIn, Scopes: stored.Scopes}, nil
+}
+
+// codeReused kullanılmış kodun sahibi olan client'ın o kullanıcı için aldığı
+// token'ları iptal eder.
+func (s *OAuthService) codeReused(code *entity.OAuthAuthorizationCode, info ClientInfo) error {
+	if err := s.tokenService.RevokeOAuthGrant(code.ClientID, code.UserID); err != nil {
+		return err
+	}
+	s.logEvent(code.UserID, "oauth_code_reused", info)
+	return oauthError(OAuthInvalidGrant, "invalid or expired authorization code")
+}
+
+// ---------------- CLIENT CREDENTIALS ----------------
+
+// ClientCredentials kullanıcı olmadan, client'ın kendi adına token üretir.
+func (s *OAuthService) ClientCredentials(client *entity.OAuthClient, scope string) (*OAuthToken, error) {
+	if client.Public || !client.AllowsGrant(entity.GrantClientCredentials) {
+		return nil, oauthError(OAuthUnauthorizedClient, "client is not allowed to use the client credentials grant")
+	}
+	scopes, err := resolveScopes(client, scope)
+	if err != nil {
+		return nil, err
+	}
+	token, expiresIn, err := s.tokenService.IssueOAuthToken(client.ID, nil, scopes)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthToken{AccessToken: token, ExpiresIn: expiresIn, Scopes: scopes}, nil
+}
+
+// ---------------- INTROSPECTION & REVOCATION ----------------
+
+// Introspect token'ın hâlâ geçerli olup olmadığını söyler (RFC 7662).
+// Client sadece kendisine verilmiş token'ları sorgulayabilir; diğerleri
+// için active=false döner.
+func (s *OAuthService) Introspect(client *entity.OAuthClient, raw string) (*Introspection, error) {
+	claims, err := s.tokenService.ParseOAuthToken(raw)
+	if err != nil || claims.ClientID != client.ID {
+		return &Introspection{}, nil
+	}
+
+	issuedAt := claims.IssuedAt.Time
+	revoked, err := s.tokenService.IsAccessTokenRevoked(claims.ID, "", claims.UserID, issuedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		revoked, err = s.tokenService.IsOAuthGrantRevoked(claims.ClientID, claims.UserID, issuedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if revoked {
+		return &Introspection{}, nil
+	}
+
+	return &Introspection{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Subject:   claims.Subject,
+		ExpiresAt: claims.ExpiresAt.Unix(),
+		IssuedAt:  issuedAt.Unix(),
+	}, nil
+}
+
+// Revoke client'ın kendi token'ını iptal eder (RFC 7009). Geçersiz veya
+// başka client'a ait token'lar sessizce yok sayılır.
+func (s *OAuthService) Revoke(client *entity.OAuthClient, raw string) error {
+	claims, err := s.tokenService.ParseOAuthToken(raw)
+	if err != nil || claims.ClientID != client.ID {
+		return nil
+	}
+	return s.tokenService.RevokeAccessToken(claims.ID, claims.ExpiresAt.Time)
+}
+
+// ---------------- CONSENTS ----------------
+
+// ListGrants kullanıcının onay verdiği client'ları döner.
+func (s *OAuthService) ListGrants(userID string) ([]OAuthGrant, error) {
+	consents, err := s.consents.ListForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	grants := make([]OAuthGrant, 0, len(consents))
+	for _, consent := range consents {
+		client, err := s.clients.FindByClientID(consent.ClientID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		grants = append(grants, OAuthGrant{Client: client, Consent: consent})
+	}
+	return grants, nil
+}
+
+// RevokeGrant onayı siler ve client'ın kullanıcı adına aldığı token'ları
+// geçersiz kılar. Onay yoksa false döner.
+func (s *OAuthService) RevokeGrant(userID, clientID string, info ClientInfo) (bool, error) {
+	if _, err := uuid.Parse(clientID); err != nil {
+		return false, nil
+	}
+	ok, err := s.consents.DeleteForUser(userID, clientID)
+	if err != nil || !ok {
+		return ok, err
+	}
+	if err := s.tokenService.RevokeOAuthGrant(clientID, userID); err != nil {
+		return false, err
+	}
+	s.logEvent(userID, "oauth_consent_revoked", info)
+	return true, nil
+}
+
+func (s *OAuthService) logEvent(userID, action string, info ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, info); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
+
+// resolveScopes boşlukla ayrılmış scope'ları client'ın izinli scope'larına
+// göre doğrular. scope verilmezse client'ın tüm scope'ları kullanılır.
+func resolveScopes(client *entity.OAuthClient, scope string) ([]string, error) {
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		return client.Scopes, nil
+	}
+	for _, s := range requested {
+		if !slices.Contains(client.Scopes, s) {
+			return nil, oauthError(OAuthInvalidScope, "scope not allowed: "+s)
+		}
+	}
+	slices.Sort(requested)
+	return slices.Compact(requested), nil
+}
+
+// verifyPKCE RFC 7636 S256: BASE64URL(SHA256(verifier)) == challenge.
+func verifyPKCE(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+func covers(granted, requested []string) bool {
+	for _, scope := range requested {
+		if !slices.Contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func union(a, b []string) []string {
+	out := slices.Concat(a, b)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// redirectWith redirect_uri'deki mevcut query parametrelerini koruyarak yenilerini ekler.
+func redirectWith(redirectURI string, params url.Values) (string, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for key, values := range params {
+		if len(values) > 0 && values[0] != "" {
+			query.Set(key, values[0])
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRedirectURI = "https://app.example.com/callback"
+
+func newOAuthService(env *testEnv) *OAuthService {
+	return NewOAuthService(
+		repository.NewOAuthClientRepository(env.db),
+		repository.NewOAuthAuthorizationCodeRepository(env.db),
+		repository.NewOAuthConsentRepository(env.db),
+		env.users, env.tokens, env.activities,
+	)
+}
+
+func createCodeClient(t *testing.T, oauth *OAuthService) *entity.OAuthClient {
+	t.Helper()
+	client, _, err := oauth.CreateClient("app", []string{testRedirectURI}, []string{entity.GrantAuthorizationCode}, entity.AllPermissions[:1], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func authorizeCode(t *testing.T, oauth *OAuthService, client *entity.OAuthClient, userID, verifier string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(verifier))
+	redirect, err := oauth.Authorize(userID, AuthorizationRequest{
+		ClientID:            client.ID,
+		RedirectURI:         testRedirectURI,
+		ResponseType:        "code",
+		State:               "state",
+		CodeChallenge:       base64.RawURLEncoding.EncodeToString(sum[:]),
+		CodeChallengeMethod: "S256",
+	}, true, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	location, err := url.Parse(redirect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location.Query().Get("code")
+}
+
+func requireInvalidGrant(t *testing.T, err error) {
+	t.Helper()
+	var oauthErr *OAuthError
+	if !errors.As(err, &oauthErr) || oauthErr.Code != OAuthInvalidGrant {
+		t.Fatalf("err = %v, want invalid_grant", err)
+	}
+}
+
+func TestExchangeCodeChecksClientBeforeConsuming(t *testing.T) {
+	env := newTestEnv(t)
+	oauth := newOAuthService(env)
+	client := createCodeClient(t, oauth)
+	other := createCodeClient(t, oauth)
+	user := env.createUser(t, "oauth@example.com")
+	verifier := strings.Repeat("v", 43)
+	code := authorizeCode(t, oauth, client, user.ID, verifier)
+
+	_, err := oauth.ExchangeCode(other, code, testRedirectURI, verifier, ClientInfo{})
+	requireInvalidGrant(t, err)
+	_, err = oauth.ExchangeCode(client, code, "https://evil.example.com/callback", verifier, ClientInfo{})
+	requireInvalidGrant(t, err)
+	_, err = oauth.ExchangeCode(client, code, testRedirectURI, strings.Repeat("x", 43), ClientInfo{})
+	requireInvalidGrant(t, err)
+
+	token, err := oauth.ExchangeCode(client, code, testRedirectURI, verifier, ClientInfo{})
+	if err != nil {
+		t.Fatalf("failed attempts burned the code: %v", err)
+	}
+	claims, err := env.tokens.ParseOAuthToken(token.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Aynı kodun tekrar kullanımı bu koddan alınan token'ları iptal eder.
+	time.Sleep(2 * time.Millisecond)
+	_, err = oauth.ExchangeCode(client, code, testRedirectURI, verifier, ClientInfo{})
+	requireInvalidGrant(t, err)
+	revoked, err := env.tokens.IsOAuthGrantRevoked(client.ID, user.ID, claims.IssuedAt.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Fatal("token issued from a reused code is still valid")
+	}
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-initial-project/config"
+	"go-initial-project/encryption"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownProvider   = errors.New("unknown identity provider")
+	ErrInvalidOIDCFlow   = errors.New("invalid or expired login attempt")
+	ErrOIDCExchange      = errors.New("identity provider rejected the login")
+	ErrIdentityLinked    = errors.New("this external account is linked to another user")
+	ErrOIDCEmailConflict = errors.New("an account with this e-mail already exists, sign in and link the provider instead")
+	ErrOIDCEmailMissing  = errors.New("identity provider did not return an e-mail address")
+)
+
+// OIDCFlow login başlatılırken üretilip callback'te kontrol edilen değerler.
+// Tarayıcıda şifreli cookie olarak tutulur; böylece callback sadece login'i
+// başlatan tarayıcıdan tamamlanabilir.
+type OIDCFlow struct {
+	Provider   string    `json:"p"`
+	State      string    `json:"s"`
+	Nonce      string    `json:"n"`
+	Verifier   string    `json:"v"`
+	LinkUserID string    `json:"l,omitempty"`
+	ExpiresAt  time.Time `json:"e"`
+}
+
+// OIDCResult callback sonucu. Linked true ise mevcut oturuma yeni bir
+// giriş yöntemi bağlanmıştır, token üretilmemelidir.
+type OIDCResult struct {
+	User     *entity.User
+	Identity *entity.Identity
+	Linked   bool
+}
+
+type oidcClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Nonce         string `json:"nonce"`
+}
+
+// oidcProvider discovery dokümanını ilk kullanımda çeker; IdP erişilemezse
+// uygulama açılışı engellenmez, sonraki istekte tekrar denenir.
+type oidcProvider struct {
+	cfg config.OIDCProviderConfig
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func (p *oidcProvider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil {
+		return p.provider, nil
+	}
+	provider, err := oidc.NewProvider(ctx, p.cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery for %s: %w", p.cfg.Name, err)
+	}
+	p.provider = provider
+	return provider, nil
+}
+
+func (p *oidcProvider) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.cfg.Scopes,
+	}
+}
+
+type OIDCService struct {
+	providers       map[string]*oidcProvider
+	userService     *UserService
+	identities      *repository.IdentityRepository
+	activityService *ActivityService
+	cipher          *encryption.Cipher
+}
+
+func NewOIDCService(
+	providers []config.OIDCProviderConfig,
+	userService *UserService,
+	identities *repository.IdentityRepository,
+	activityService *ActivityService,
+	cipher *encryption.Cipher,
+) *OIDCService {
+	s := &OIDCService{
+		providers:       make(map[string]*oidcProvider, len(providers)),
+		userService:     userService,
+		identities:      identities,
+		activityService: activityService,
+		cipher:          cipher,
+	}
+	for _, cfg := range providers {
+		s.providers[cfg.Name] = &oidcProvider{cfg: cfg}
+	}
+	return s
+}
+
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Begin IdP'nin authorization URL'ini ve tarayıcıda saklanacak şifreli flow
+// değerini döner. linkUserID doluysa callback yeni kullanıcı açmak yerine
+// identity'yi bu kullanıcıya bağlar.
+func (s *OIDCService) Begin(ctx context.Context, name, linkUserID string) (string, string, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	flow := OIDCFlow{
+		Provider:   name,
+		State:      state,
+		Nonce:      nonce,
+		Verifier:   oauth2.GenerateVerifier(),
+		LinkUserID: linkUserID,
+		ExpiresAt:  time.Now().Add(config.AppConfig.OIDC.FlowTTL),
+	}
+	sealed, err := s.sealFlow(flow)
+	if err != nil {
+		return "", "", err
+	}
+
+	url := p.oauth2Config(provider).AuthCodeURL(state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(flow.Verifier),
+	)
+	return url, sealed, nil
+}
+
+// Complete state'i kontrol eder, code'u PKCE verifier ile token'a çevirir,
+// ID token'ı sağlayıcının JWKS'i ile doğrular ve kullanıcıyı bulur/oluşturur.
+func (s *OIDCService) Complete(ctx context.Context, name, code, state, sealedFlow string, client ClientInfo) (*OIDCResult, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	flow, err := s.openFlow(sealedFlow)
+	if err != nil || flow.Provider != name || time.Now().After(flow.ExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(flow.State), []byte(state)) != 1 {
+		return nil, ErrInvalidOIDCFlow
+	}
+
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+	if err != nil {
+		log.Printf("⚠️ oidc %s code exchange failed: %v", name, err)
+		return nil, ErrOIDCExchange
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, ErrOIDCExchange
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.cfg.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		log.Printf("⚠️ oidc %s id token rejected: %v", name, err)
+		return nil, ErrOIDCExchange
+	}
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, ErrOIDCExchange
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(flow.Nonce)) != 1 {
+		return nil, ErrInvalidOIDCFlow
+	}
+
+	if flow.LinkUserID != "" {
+		return s.link(name, idToken.Subject, claims, flow.LinkUserID, client)
+	}
+	return s.signIn(name, idToken.Subject, claims, client)
+}
+
+func (s *OIDCService) ListIdentities(userID string) ([]entity.Identity, error) {
+	return s.identities.ListForUser(userID)
+}
+
+func (s *OIDCService) Unlink(userID, id string, client ClientInfo) (bool, error) {
+	ok, err := s.identities.DeleteForUser(userID, id)
+	if err != nil || !ok {
+		return ok, err
+	}
+	s.logEvent(userID, "identity_unlinked", client)
+	return true, nil
+}
+
+// signIn identity varsa sahibini döner. Yoksa sağlayıcının doğruladığı
+// e-posta ile eşleşen kullanıcıya bağlar veya yeni kullanıcı oluşturur.
+func (s *OIDCService) signIn(provider, subject string, claims oidcClaims, client ClientInfo) (*OIDCResult, error) {
+	identity, err := s.identities.FindBySubject(provider, subject)
+	if err == nil {
+		user, err := s.userService.First(map[string]interface{}{"id": identity.UserID})
+		if err != nil {
+			return nil, err
+		}
+		return &OIDCResult{User: &user, Identity: identity}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, ErrOIDCEmailMissing
+	}
+	user, err := s.userService.FindByEmail(claims.Email)
+	switch {
+	case err == nil:
+		// Doğrulanmamış e-posta ile eşleştirme hesap ele geçirmeye açıktır. Yerel
+		// hesap doğrulanmamışsa da bağlanmaz; e-postayı önceden kaydedip şifresini
+		// bilen biri provider ile giriş yapan gerçek sahibin hesabına erişebilirdi.
+		if !claims.EmailVerified || !user.EmailVerified() {
+			return nil, ErrOIDCEmailConflict
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		user, err = s.createUser(claims)
+		if err != nil {
+			return nil, err
+		}
+		s.logEvent(user.ID, "oidc_registered", client)
+	default:
+		return nil, err
+	}
+
+	identity = &entity.Identity{UserID: user.ID, Provider: provider, Subject: subject, Email: claims.Email}
+	if err := s.identities.Create(identity); err != nil {
+		return nil, err
+	}
+	s.logEvent(user.ID, "identity_linked", client)
+	return &OIDCResult{User: user, Identity: identity}, nil
+}
+
+func (s *OIDCService) link(provider, subject string, claims oidcClaims, userID string, client ClientInfo) (*OIDCResult, error) {
+	user, err := s.userService.First(map[string]interface{}{"id": userID})
+	if err != nil {
+		return nil, ErrInvalidOIDCFlow
+	}
+
+	identity, err := s.identities.FindBySubject(provider, subject)
+	switch {
+	case err == nil:
+		if identity.UserID != userID {
+			return nil, ErrIdentityLinked
+		}
+		return &OIDCResult{User: &user, Identity: identity, Linked: true}, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	identity = &entity.Identity{UserID: userID, Provider: provider, Subject: subject, Email: claims.Email}
+	if err := s.identities.Create(identity); err != nil {
+		return nil, err
+	}
+	s.logEvent(userID, "identity_linked", client)
+	return &OIDCResult{User: &user, Identity: identity, Linked: true}, nil
+}
+
+// createUser şifresiz kullanıcı açar; şifre ile giriş ancak şifre
+// sıfırlandıktan sonra mümkün olur.
+func (s *OIDCService) createUser(claims oidcClaims) (*entity.User, error) {
+	first, last := claims.GivenName, claims.FamilyName
+	if first == "" && last == "" {
+		first, last, _ = strings.Cut(claims.Name, " ")
+	}
+	if first == "" {
+		first, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	user := entity.User{FirstName: first, LastName: last, Email: claims.Email}
+	if claims.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	created, err := s.userService.Create(user)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (s *OIDCService) sealFlow(flow OIDCFlow) (string, error) {
+	raw, err := json.Marshal(flow)
+	if err != nil {
+		return "", err
+	}
+	return s.cipher.Encrypt(string(raw))
+}
+
+func (s *OIDCService) openFlow(sealed string) (*OIDCFlow, error) {
+	if sealed == "" {
+		return nil, ErrInvalidOIDCFlow
+	}
+	raw, err := s.cipher.Decrypt(sealed)
+	if err != nil {
+		return nil, err
+	}
+	var flow OIDCFlow
+	if err := json.Unmarshal([]byte(raw), &flow); err != nil {
+		return nil, err
+	}
+	return &flow, nil
+}
+
+func (s *OIDCService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/encryption"
+	"go-initial-project/repository"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testOIDCClientID = "test-client"
+
+// mockIssuer discovery, JWKS ve token uçlarını sunan sahte OIDC sağlayıcısı.
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]issuedCode
+}
+
+// issuedCode kullanıcının IdP'de girişi sonrası code'a bağlanan değerler.
+type issuedCode struct {
+	challenge     string
+	nonce         string
+	subject       string
+	email         string
+	emailVerified bool
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockIssuer{key: key, codes: make(map[string]issuedCode)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                m.server.URL,
+			"authorization_endpoint":                m.server.URL + "/authorize",
+			"token_endpoint":                        m.server.URL + "/token",
+			"jwks_uri":                              m.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "test", "alg": "RS256", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", m.token)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	m.mu.Lock()
+	code, ok := m.codes[r.Form.Get("code")]
+	delete(m.codes, r.Form.Get("code"))
+	m.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            m.server.URL,
+		"aud":            testOIDCClientID,
+		"sub":            code.subject,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          code.nonce,
+		"email":          code.email,
+		"email_verified": code.emailVerified,
+		"given_name":     "Test",
+		"family_name":    "User",
+	})
+	idToken.Header["kid"] = "test"
+	signed, err := idToken.SignedString(m.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+// login kullanıcının authorization URL'inde giriş yapmasını simüle eder ve
+// callback'e dönecek code'u üretir. nonce boşsa URL'deki nonce kullanılır.
+func (m *mockIssuer) login(t *testing.T, authURL, nonce, subject, email string, emailVerified bool) string {
+	t.Helper()
+	location, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := location.Query()
+	if nonce == "" {
+		nonce = query.Get("nonce")
+	}
+	code := "code-" + subject + "-" + query.Get("state")
+	m.mu.Lock()
+	m.codes[code] = issuedCode{
+		challenge:     query.Get("code_challenge"),
+		nonce:         nonce,
+		subject:       subject,
+		email:         email,
+		emailVerified: emailVerified,
+	}
+	m.mu.Unlock()
+	return code
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func newOIDCService(t *testing.T, env *testEnv, issuer *mockIssuer) *OIDCService {
+	t.Helper()
+	cipher, err := encryption.New([]byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewOIDCService([]config.OIDCProviderConfig{{
+		Name:        "mock",
+		Issuer:      issuer.server.URL,
+		ClientID:    testOIDCClientID,
+		RedirectURL: "https://app.example.com/oidc/callback/mock",
+		Scopes:      []string{"openid", "email", "profile"},
+	}}, env.users, repository.NewIdentityRepository(env.db), env.activities, cipher)
+}
+
+// completeLogin Begin ile başlayan akışı IdP girişi ile tamamlar.
+func completeLogin(t *testing.T, oidcService *OIDCService, issuer *mockIssuer, linkUserID, subject, email string, emailVerified bool) (*OIDCResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	authURL, sealed, err := oidcService.Begin(ctx, "mock", linkUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := issuer.login(t, authURL, "", subject, email, emailVerified)
+	state := mustQuery(t, authURL, "state")
+	return oidcService.Complete(ctx, "mock", code, state, sealed, ClientInfo{})
+}
+
+func mustQuery(t *testing.T, rawURL, key string) string {
+	t.Helper()
+	location, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location.Query().Get(key)
+}
+
+func TestOIDCCompleteRejectsStateMismatch(t *testing.T) {
+	env := newTestEnv(t)
+	issuer := newMockIssuer(t)
+	oidcService := newOIDCService(t, env, issuer)
+	ctx := context.Background()
+
+	authURL, sealed, err := oidcService.Begin(ctx, "mock", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := issuer.login(t, authURL, "", "subject", "state@example.com", true)
+
+	_, err = oidcService.Complete(ctx, "mock", code, "forged-state", sealed, ClientInfo{})
+	if !errors.Is(err, ErrInvalidOIDCFlow) {
+		t.Fatalf("err = %v, want ErrInvalidOIDCFlow", err)
+	}
+}
+
+func TestOIDCCompleteRejectsNonceMismatch(t *testing.T) {
+	env := newTestEnv(t)
+	issuer := newMockIssuer(t)
+	oidcService := newOIDCService(t, env, issuer)
+	ctx := context.Background()
+
+	authURL, sealed, err := oidcService.Begin(ctx, "mock", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := issuer.login(t, authURL, "replayed-nonce", "subject", "nonce@example.com", true)
+
+	_, err = oidcService.Complete(ctx, "mock", code, mustQuery(t, authURL, "state"), sealed, ClientInfo{})
+	if !errors.Is(err, ErrInvalidOIDCFlow) {
+		t.Fatalf("err = %v, want ErrInvalidOIDCFlow", err)
+	}
+}
+
+func TestOIDCUnverifiedEmailDoesNotTakeOverAccount(t *testing.T) {
+	env := newTestEnv(t)
+	issuer := newMockIssuer(t)
+	oidcService := newOIDCService(t, env, issuer)
+	env.createUser(t, "victim@example.com")
+
+	_, err := completeLogin(t, oidcService, issuer, "", "attacker", "victim@example.com", false)
+	if !errors.Is(err, ErrOIDCEmailConflict) {
+		t.Fatalf("err = %v, want ErrOIDCEmailConflict", err)
+	}
+}
+
+func TestOIDCDoesNotLinkUnverifiedLocalAccount(t *testing.T) {
+	env := newTestEnv(t)
+	issuer := newMockIssuer(t)
+	oidcService := newOIDCService(t, env, issuer)
+	// Saldırgan kurbanın e-postasıyla önceden kayıt olmuş, e-postayı doğrulamamış.
+	squatted := env.createUser(t, "squatted@example.com")
+
+	_, err := completeLogin(t, oidcService, issuer, "", "real-owner", "squatted@example.com", true)
+	if !errors.Is(err, ErrOIDCEmailConflict) {
+		t.Fatalf("err = %v, want ErrOIDCEmailConflict", err)
+	}
+
+	// Yerel hesap doğrulandıktan sonra provider hesabı otomatik bağlanır.
+	if err := env.users.UpdateWhere(map[string]interface{}{"id": squatted.ID}, map[string]interface{}{"email_verified_at": time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := completeLogin(t, oidcService, issuer, "", "real-owner", "squatted@example.com", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.User.ID != squatted.ID {
+		t.Fatalf("signed in as %s, want %s", result.User.ID, squatted.ID)
+	}
+}
+
+func TestOIDCLinkRejectsSubjectLinkedToAnotherUser(t *testing.T) {
+	env := newTestEnv(t)
+	issuer := newMockIssuer(t)
+	oidcService := newOIDCService(t, env, issuer)
+
+	owner, err := completeLogin(t, oidcService, issuer, "", "shared", "owner@example.com", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := env.createUser(t, "other@example.com")
+
+	_, err = completeLogin(t, oidcService, issuer, other.ID, "shared", "owner@example.com", true)
+	if !errors.Is(err, ErrIdentityLinked) {
+		t.Fatalf("err = %v, want ErrIdentityLinked", err)
+	}
+
+	// Sahibi aynı subject ile tekrar giriş yapabilir.
+	again, err := completeLogin(t, oidcService, issuer, "", "shared", "owner@example.com", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.User.ID != owner.User.ID {
+		t.Fatalf("signed in as %s, want %s", again.User.ID, owner.User.ID)
+	}
+}
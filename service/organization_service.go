@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"go-initial-project/tenant"
+	"log"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrgSlugTaken     = errors.New("organization slug is already taken")
+	ErrInvalidOrgSlug   = errors.New("a slug could not be derived from the name, please provide one")
+	ErrNotMember        = errors.New("not a member of this organization")
+	ErrOrgOwnerRequired = errors.New("only owners can grant, change or remove the owner role")
+	ErrLastOwner        = errors.New("an organization must keep at least one owner")
+)
+
+type OrganizationService struct {
+	orgs            *repository.OrganizationRepository
+	memberships     *repository.MembershipRepository
+	tokenService    *TokenService
+	activityService *ActivityService
+}
+
+func NewOrganizationService(
+	orgs *repository.OrganizationRepository,
+	memberships *repository.MembershipRepository,
+	tokenService *TokenService,
+	activityService *ActivityService,
+) *OrganizationService {
+	return &OrganizationService{
+		orgs:            orgs,
+		memberships:     memberships,
+		tokenService:    tokenService,
+		activityService: activityService,
+	}
+}
+
+// Create organizasyonu oluşturur ve user'ı owner yapar. slug boşsa addan türetilir.
+func (s *OrganizationService) Create(ctx context.Context, user *entity.User, name, slug string, client ClientInfo) (*entity.Organization, error) {
+	if slug == "" {
+		slug = slugify(name)
+	}
+	if slug == "" {
+		return nil, ErrInvalidOrgSlug
+	}
+
+	org := &entity.Organization{Name: name, Slug: slug}
+	owner := &entity.Membership{UserID: user.ID, Role: entity.OrgRoleOwner}
+	if err := s.orgs.CreateWithOwner(ctx, org, owner); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrOrgSlugTaken
+		}
+		return nil, err
+	}
+
+	s.logEvent(user.ID, "organization_created", client)
+	return org, nil
+}
+
+// ListForUser kullanıcının üye olduğu organizasyonları rolleriyle döner.
+func (s *OrganizationService) ListForUser(ctx context.Context, userID string) ([]entity.UserOrganization, error) {
+	return s.orgs.ListForUser(ctx, userID)
+}
+
+// Membership kullanıcının ctx'teki tenant'taki üyeliğini döner; üye değilse ErrNotMember.
+func (s *OrganizationService) Membership(ctx context.Context, userID string) (*entity.Membership, error) {
+	membership, err := s.memberships.FindForUser(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotMember
+	}
+	return membership, err
+}
+
+// Switch oturumun aktif organizasyonunu değiştirir ve "org" claim'li yeni
+// access token döner.
+func (s *OrganizationService) Switch(ctx context.Context, user *entity.User, sessionID, organizationID string) (string, int64, error) {
+	if _, err := s.Membership(tenant.NewContext(ctx, organizationID), user.ID); err != nil {
+		return "", 0, err
+	}
+	return s.tokenService.SwitchOrganization(user, sessionID, organizationID)
+}
+
+// Current ctx'teki tenant'ın organizasyonunu döner.
+func (s *OrganizationService) Current(ctx context.Context) (*entity.Organization, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, tenant.ErrNoTenant
+	}
+	org, err := s.orgs.FindByIDCtx(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// Rename aktif organizasyonun adını değiştirir; slug değişmez.
+func (s *OrganizationService) Rename(ctx context.Context, actorID, name string, client ClientInfo) (*entity.Organization, error) {
+	org, err := s.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.orgs.UpdateColumns(org.ID, map[string]interface{}{"name": name}); err != nil {
+		return nil, err
+	}
+	org.Name = name
+
+	s.logEvent(actorID, "organization_renamed", client)
+	return org, nil
+}
+
+// Delete aktif organizasyonu ve tüm üyeliklerini siler, bekleyen davetlerini iptal eder.
+func (s *OrganizationService) Delete(ctx context.Context, actorID string, client ClientInfo) error {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return tenant.ErrNoTenant
+	}
+	if err := s.orgs.DeleteWithMemberships(ctx, id); err != nil {
+		return err
+	}
+
+	s.logEvent(actorID, "organization_deleted", client)
+	return nil
+}
+
+// Members aktif organizasyonun üyelerini döner.
+func (s *OrganizationService) Members(ctx context.Context) ([]entity.Membership, error) {
+	return s.memberships.ListMembers(ctx)
+}
+
+// ChangeRole üyenin rolünü değiştirir. Owner rolünü sadece owner'lar verebilir
+// veya geri alabilir; son owner düşürülemez.
+func (s *OrganizationService) ChangeRole(ctx context.Context, actorID, actorRole, userID, role string, client ClientInfo) error {
+	err := s.memberships.WithTransaction(ctx, func(repo *repository.MembershipRepository) error {
+		target, err := s.lockMember(ctx, repo, actorRole, userID)
+		if err != nil {
+			return err
+		}
+		if role == entity.OrgRoleOwner && actorRole != entity.OrgRoleOwner {
+			return ErrOrgOwnerRequired
+		}
+		if target.Role == role {
+			return nil
+		}
+		if err := ensureAnotherOwner(ctx, repo, target); err != nil {
+			return err
+		}
+		return repo.UpdateRole(ctx, userID, role)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logEvent(actorID, "org_member_role_changed", client)
+	return nil
+}
+
+// RemoveMember üyeyi organizasyondan çıkarır; userID actorID ise üye ayrılır.
+// Son owner çıkarılamaz.
+func (s *OrganizationService) RemoveMember(ctx context.Context, actorID, actorRole, userID string, client ClientInfo) error {
+	err := s.memberships.WithTransaction(ctx, func(repo *repository.MembershipRepository) error {
+		target, err := s.lockMember(ctx, repo, actorRole, userID)
+		if err != nil {
+			return err
+		}
+		if err := ensureAnotherOwner(ctx, repo, target); err != nil {
+			return err
+		}
+		return repo.Remove(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	action := "org_member_removed"
+	if userID == actorID {
+		action = "org_left"
+	}
+	s.logEvent(actorID, action, client)
+	return nil
+}
+
+// lockMember üyeliği kilitler; owner'lara sadece owner'lar dokunabilir.
+func (s *OrganizationService) lockMember(ctx context.Context, repo *repository.MembershipRepository, actorRole, userID string) (*entity.Membership, error) {
+	target, err := repo.FindForUserForUpdate(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotMember
+	}
+	if err != nil {
+		return nil, err
+	}
+	if target.Role == entity.OrgRoleOwner && actorRole != entity.OrgRoleOwner {
+		return nil, ErrOrgOwnerRequired
+	}
+	return target, nil
+}
+
+// ensureAnotherOwner target owner ise organizasyonda başka bir owner kaldığını doğrular.
+func ensureAnotherOwner(ctx context.Context, repo *repository.MembershipRepository, target *entity.Membership) error {
+	if target.Role != entity.OrgRoleOwner {
+		return nil
+	}
+	owners, err := repo.LockOwners(ctx)
+	if err != nil {
+		return err
+	}
+	if len(owners) <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func (s *OrganizationService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
+
+// slugReplacer Türkçe harfleri slug'da karşılıklarına çevirir.
+var slugReplacer = strings.NewReplacer("i\u0307", "i", "ç", "c", "ğ", "g", "ı", "i", "ö", "o", "ş", "s", "ü", "u")
+
+// slugify "Acme, Inc." → "acme-inc", "Çiçek Yazılım" → "cicek-yazilim".
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range slugReplacer.Replace(strings.ToLower(name)) {
+		if b.Len() >= maxSlugLength {
+			break
+		}
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+const maxSlugLength = 64
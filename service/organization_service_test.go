@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/mailer"
+	"go-initial-project/repository"
+	"go-initial-project/tenant"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+// recordingMailer gönderilen e-postaları saklar.
+type recordingMailer struct {
+	mu       sync.Mutex
+	messages []mailer.Message
+}
+
+func (m *recordingMailer) Send(msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+var invitationLink = regexp.MustCompile(`token=(\S+)`)
+
+// lastToken son e-postadaki davet linkinin token'ını döner.
+func (m *recordingMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.messages) == 0 {
+		t.Fatal("no e-mail sent")
+	}
+	match := invitationLink.FindStringSubmatch(m.messages[len(m.messages)-1].Body)
+	if match == nil {
+		t.Fatal("e-mail has no invitation link")
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func newOrganizationService(env *testEnv) *OrganizationService {
+	return NewOrganizationService(
+		repository.NewOrganizationRepository(env.db),
+		repository.NewMembershipRepository(env.db),
+		env.tokens, env.activities,
+	)
+}
+
+func newInvitationService(env *testEnv, orgs *OrganizationService, mail mailer.Mailer) *InvitationService {
+	return NewInvitationService(repository.NewInvitationRepository(env.db), orgs, env.users, env.activities, mail)
+}
+
+// createOrganization owner için organizasyon açar ve onun tenant context'ini döner.
+func createOrganization(t *testing.T, orgs *OrganizationService, owner *entity.User) context.Context {
+	t.Helper()
+	org, err := orgs.Create(context.Background(), owner, "Acme", "", ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tenant.NewContext(context.Background(), org.ID)
+}
+
+func TestDeleteOrganizationRevokesPendingInvitations(t *testing.T) {
+	env := newTestEnv(t)
+	orgs := newOrganizationService(env)
+	mail := &recordingMailer{}
+	invitations := newInvitationService(env, orgs, mail)
+	owner := env.createUser(t, "owner@example.com")
+	ctx := createOrganization(t, orgs, owner)
+
+	invitation, err := invitations.Create(ctx, owner, entity.OrgRoleOwner, "invitee@example.com", entity.OrgRoleMember, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := mail.lastToken(t)
+
+	if err := orgs.Delete(ctx, owner.ID, ClientInfo{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored entity.Invitation
+	if err := env.db.WithContext(ctx).First(&stored, "id = ?", invitation.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != entity.InvitationRevoked {
+		t.Fatalf("invitation status = %s, want %s", stored.Status, entity.InvitationRevoked)
+	}
+	if _, err := invitations.Pending(context.Background(), token); !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("err = %v, want ErrInvalidInvitation", err)
+	}
+}
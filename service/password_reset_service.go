@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/mailer"
+	"go-initial-project/repository"
+	"log"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+type PasswordResetService struct {
+	userService     *UserService
+	tokenService    *TokenService
+	activityService *ActivityService
+	resets          *repository.PasswordResetTokenRepository
+	mailer          mailer.Mailer
+}
+
+func NewPasswordResetService(
+	userService *UserService,
+	tokenService *TokenService,
+	activityService *ActivityService,
+	resets *repository.PasswordResetTokenRepository,
+	mail mailer.Mailer,
+) *PasswordResetService {
+	return &PasswordResetService{
+		userService:     userService,
+		tokenService:    tokenService,
+		activityService: activityService,
+		resets:          resets,
+		mailer:          mail,
+	}
+}
+
+// RequestReset kullanıcıya sıfırlama linki gönderir. Kayıtlı olmayan e-postalar
+// için de hata dönmez; böylece endpoint hesap varlığını sızdırmaz.
+func (s *PasswordResetService) RequestReset(email string, client ClientInfo) error {
+	user, err := s.userService.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	rawToken, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ttl := config.AppConfig.Auth.PasswordResetTTL
+	if err := s.resets.InvalidateForUser(user.ID, now); err != nil {
+		return err
+	}
+	if err := s.resets.Create(&entity.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(rawToken),
+		ExpiresAt: now.Add(ttl),
+	}); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", config.AppConfig.App.FrontendURL, url.QueryEscape(rawToken))
+	if err := s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password. It expires in %s and can only be used once.\n\n%s\n\nIf you did not ask for this, you can ignore this e-mail.\n",
+			user.FirstName, ttl, link,
+		),
+	}); err != nil {
+		return err
+	}
+
+	s.logEvent(user.ID, "password_reset_requested", client)
+	return nil
+}
+
+// Reset token'ı tüketir, şifreyi değiştirir ve kullanıcının tüm oturumlarını kapatır.
+// Yeni şifre politikaya uymuyorsa token tüketilmez; kullanıcı aynı linkle
+// tekrar deneyebilir.
+func (s *PasswordResetService) Reset(rawToken, newPassword string, client ClientInfo) error {
+	hash := hashToken(rawToken)
+	now := time.Now()
+
+	pending, err := s.resets.FindActive(hash, now)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	user, err := s.userService.First(map[string]interface{}{"id": pending.UserID})
+	if err != nil {
+		return err
+	}
+	if err := s.userService.ValidatePassword(&user, newPassword); err != nil {
+		return err
+	}
+
+	token, err := s.resets.Consume(hash, now)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	userID := token.UserID
+
+	if err := s.userService.UpdatePassword(userID, newPassword); err != nil {
+		return err
+	}
+	if err := s.tokenService.RevokeUserTokens(userID); err != nil {
+		return err
+	}
+
+	s.logEvent(userID, "password_reset", client)
+	return nil
+}
+
+func (s *PasswordResetService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"log"
+	"time"
+)
+
+var (
+	ErrWrongPassword     = errors.New("current password is incorrect")
+	ErrPasswordUnchanged = errors.New("new password must be different from the current one")
+	ErrNoPasswordSet     = errors.New("account has no password, use the password reset flow to set one")
+)
+
+// PasswordService oturum açmış kullanıcının şifre işlemleri.
+type PasswordService struct {
+	userService     *UserService
+	sessionService  *SessionService
+	activityService *ActivityService
+	throttle        *LoginThrottleService
+	resets          *repository.PasswordResetTokenRepository
+	apiKeys         *repository.APIKeyRepository
+}
+
+func NewPasswordService(
+	userService *UserService,
+	sessionService *SessionService,
+	activityService *ActivityService,
+	throttle *LoginThrottleService,
+	resets *repository.PasswordResetTokenRepository,
+	apiKeys *repository.APIKeyRepository,
+) *PasswordService {
+	return &PasswordService{
+		userService:     userService,
+		sessionService:  sessionService,
+		activityService: activityService,
+		throttle:        throttle,
+		resets:          resets,
+		apiKeys:         apiKeys,
+	}
+}
+
+// Change mevcut şifreyi doğrulayıp yenisini kaydeder. İstek yapılan oturum
+// hariç tüm oturumlar, API anahtarları ve bekleyen sıfırlama linkleri iptal edilir. Yanlış
+// mevcut şifre denemeleri login ile aynı sayaca yazılır.
+func (s *PasswordService) Change(user *entity.User, current, next, sessionID string, client ClientInfo) error {
+	if !user.HasPassword() {
+		return ErrNoPasswordSet
+	}
+
+	key := EmailThrottleKey(user.Email)
+	if err := s.throttle.Check(key); err != nil {
+		return err
+	}
+	if !s.userService.CheckPassword(user, current) {
+		if err := s.throttle.RecordFailure(client, key); err != nil {
+			log.Printf("⚠️ failed to record password change failure: %v", err)
+		}
+		return ErrWrongPassword
+	}
+	if current == next {
+		return ErrPasswordUnchanged
+	}
+	if err := s.userService.ValidatePassword(user, next); err != nil {
+		return err
+	}
+
+	if err := s.userService.UpdatePassword(user.ID, next); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := s.resets.InvalidateForUser(user.ID, now); err != nil {
+		return err
+	}
+	if err := s.apiKeys.RevokeAllForUser(user.ID, now); err != nil {
+		return err
+	}
+	if _, err := s.sessionService.RevokeOthers(user.ID, sessionID); err != nil {
+		return err
+	}
+
+	if err := s.activityService.LogEvent(user.ID, "password_changed", client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+	return nil
+}
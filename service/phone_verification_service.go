@@ -0,0 +1,160 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"go-initial-project/sms"
+	"log"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNoPhone                    = errors.New("no phone number on the account")
+	ErrPhoneAlreadyVerified       = errors.New("phone number is already verified")
+	ErrPhoneVerificationThrottled = errors.New("verification code was sent recently")
+	ErrInvalidPhoneCode           = errors.New("invalid or expired verification code")
+)
+
+type PhoneVerificationService struct {
+	repo            *repository.PhoneVerificationRepository
+	userService     *UserService
+	activityService *ActivityService
+	sender          sms.Sender
+}
+
+func NewPhoneVerificationService(
+	repo *repository.PhoneVerificationRepository,
+	userService *UserService,
+	activityService *ActivityService,
+	sender sms.Sender,
+) *PhoneVerificationService {
+	return &PhoneVerificationService{
+		repo:            repo,
+		userService:     userService,
+		activityService: activityService,
+		sender:          sender,
+	}
+}
+
+// Send kullanıcının telefonuna 6 haneli bir kod gönderir; önceki kodlar
+// geçersiz olur. Son gönderimin üzerinden PhoneVerificationResendInterval
+// geçmediyse ErrPhoneVerificationThrottled döner.
+func (s *PhoneVerificationService) Send(user *entity.User, client ClientInfo) error {
+	if user.Phone == "" {
+		return ErrNoPhone
+	}
+	if user.PhoneVerified() {
+		return ErrPhoneAlreadyVerified
+	}
+
+	now := time.Now()
+	last, err := s.repo.LatestForUser(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if last != nil && now.Sub(last.CreatedAt) < config.AppConfig.Auth.PhoneVerificationResendInterval {
+		return ErrPhoneVerificationThrottled
+	}
+
+	code, err := randomDigits(6)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.InvalidateForUser(user.ID, now); err != nil {
+		return err
+	}
+	ttl := config.AppConfig.Auth.PhoneVerificationTTL
+	if err := s.repo.Create(&entity.PhoneVerification{
+		UserID:    user.ID,
+		Phone:     user.Phone,
+		CodeHash:  hashToken(user.ID + ":" + code),
+		ExpiresAt: now.Add(ttl),
+	}); err != nil {
+		return err
+	}
+
+	if err := s.sender.Send(sms.Message{
+		To:   user.Phone,
+		Body: fmt.Sprintf("Your %s verification code is %s. It expires in %s.", config.AppConfig.App.Name, code, ttl),
+	}); err != nil {
+		return err
+	}
+
+	s.logEvent(user.ID, "phone_verification_sent", client)
+	return nil
+}
+
+// Verify kodu kontrol eder ve telefonu doğrulanmış işaretler. Her kod için en
+// fazla PhoneVerificationMaxAttempts deneme yapılabilir; kod, gönderildiği
+// numara hâlâ hesaptaysa geçerlidir.
+func (s *PhoneVerificationService) Verify(user *entity.User, code string, client ClientInfo) error {
+	if user.Phone == "" {
+		return ErrNoPhone
+	}
+	if user.PhoneVerified() {
+		return ErrPhoneAlreadyVerified
+	}
+
+	now := time.Now()
+	verification, err := s.repo.FindActive(user.ID, user.Phone, now)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidPhoneCode
+		}
+		return err
+	}
+
+	allowed, err := s.repo.RecordAttempt(verification.ID, config.AppConfig.Auth.PhoneVerificationMaxAttempts)
+	if err != nil {
+		return err
+	}
+	expected := verification.CodeHash
+	if !allowed || subtle.ConstantTimeCompare([]byte(hashToken(user.ID+":"+code)), []byte(expected)) != 1 {
+		s.logEvent(user.ID, "phone_verification_failed", client)
+		return ErrInvalidPhoneCode
+	}
+
+	consumed, err := s.repo.Consume(verification.ID, now)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidPhoneCode
+	}
+
+	// Numara bu arada değiştiyse doğrulama eski numaraya yazılmaz.
+	if err := s.userService.UpdateWhere(
+		map[string]interface{}{"id": user.ID, "phone": verification.Phone},
+		map[string]interface{}{"phone_verified_at": now},
+	); err != nil {
+		return err
+	}
+	user.PhoneVerifiedAt = &now
+
+	s.logEvent(user.ID, "phone_verified", client)
+	return nil
+}
+
+func (s *PhoneVerificationService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
+
+// randomDigits n haneli, başında sıfır olabilen rastgele sayısal kod üretir.
+func randomDigits(n int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	v, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", n, v), nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RevocationStore süresi dolmadan iptal edilen token'ları tutar.
+// Kayıtlar expiresAt geçince otomatik olarak düşer; o andan sonra
+// token zaten süresi dolduğu için reddedilir.
+type RevocationStore interface {
+	Revoke(key string, expiresAt time.Time) error
+	// RevokedAt key iptal edildiyse iptal zamanını ve true döner.
+	RevokedAt(key string) (time.Time, bool, error)
+	// RevokeOnce key aktif olarak iptal edilmemişse iptal eder ve true döner.
+	// Kontrol ve yazma atomiktir; tek kullanımlık değerler için kullanılır.
+	RevokeOnce(key string, expiresAt time.Time) (bool, error)
+}
+
+// ---------------- IN-MEMORY ----------------
+
+type memoryRevocation struct {
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+// MemoryRevocationStore tek instance'lı kurulumlar ve geliştirme için.
+// Process yeniden başlarsa kayıtlar kaybolur.
+type MemoryRevocationStore struct {
+	mu      sync.RWMutex
+	entries map[string]memoryRevocation
+}
+
+func NewMemoryRevocationStore(cleanupInterval time.Duration) *MemoryRevocationStore {
+	s := &MemoryRevocationStore{entries: make(map[string]memoryRevocation)}
+	go s.janitor(cleanupInterval)
+	return s
+}
+
+func (s *MemoryRevocationStore) Revoke(key string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryRevocation{revokedAt: time.Now(), expiresAt: expiresAt}
+	return nil
+}
+
+func (s *MemoryRevocationStore) RevokeOnce(key string, expiresAt time.Time) (bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expiresAt) {
+		return false, nil
+	}
+	s.entries[key] = memoryRevocation{revokedAt: now, expiresAt: expiresAt}
+	return true, nil
+}
+
+func (s *MemoryRevocationStore) RevokedAt(key string) (time.Time, bool, error) {
+	s.mu.RLock()
+	entry, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return time.Time{}, false, nil
+	}
+	return entry.revokedAt, true, nil
+}
+
+func (s *MemoryRevocationStore) purge(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func (s *MemoryRevocationStore) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		s.purge(now)
+	}
+}
+
+// ---------------- DATABASE ----------------
+
+// DBRevocationStore birden fazla instance arasında paylaşılan kalıcı store.
+type DBRevocationStore struct {
+	repo *repository.RevokedTokenRepository
+}
+
+func NewDBRevocationStore(repo *repository.RevokedTokenRepository, cleanupInterval time.Duration) *DBRevocationStore {
+	s := &DBRevocationStore{repo: repo}
+	go s.janitor(cleanupInterval)
+	return s
+}
+
+func (s *DBRevocationStore) Revoke(key string, expiresAt time.Time) error {
+	return s.repo.Upsert(entity.RevokedToken{
+		Key:       key,
+		RevokedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}, []string{"key"})
+}
+
+func (s *DBRevocationStore) RevokeOnce(key string, expiresAt time.Time) (bool, error) {
+	return s.repo.InsertIfAbsent(entity.RevokedToken{
+		Key:       key,
+		RevokedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	})
+}
+
+func (s *DBRevocationStore) RevokedAt(key string) (time.Time, bool, error) {
+	token, err := s.repo.FindActive(key, time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+	return token.RevokedAt, true, nil
+}
+
+func (s *DBRevocationStore) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if _, err := s.repo.DeleteExpired(now); err != nil {
+			log.Println("❌ Revoked token cleanup err:", err)
+		}
+	}
+}
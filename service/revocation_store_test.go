@@ -0,0 +1,70 @@
+package service
+
+import (
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConsumeNonceConcurrent(t *testing.T) {
+	env := newTestEnv(t)
+	stores := map[string]RevocationStore{
+		"memory": NewMemoryRevocationStore(time.Hour),
+		"db":     env.revocations,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			tokens := &TokenService{revocations: store}
+			expiresAt := time.Now().Add(time.Minute)
+
+			const workers = 16
+			var fresh atomic.Int32
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					ok, err := tokens.ConsumeNonce("nonce-"+name, expiresAt)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if ok {
+						fresh.Add(1)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if got := fresh.Load(); got != 1 {
+				t.Fatalf("nonce consumed %d times, want 1", got)
+			}
+		})
+	}
+}
+
+func TestRevokeOnceReplacesExpiredEntry(t *testing.T) {
+	env := newTestEnv(t)
+	repo := repository.NewRevokedTokenRepository(env.db)
+	past := time.Now().Add(-time.Minute)
+	if err := repo.Create(&entity.RevokedToken{Key: "jti:stale", RevokedAt: past, ExpiresAt: past}); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := env.revocations.RevokeOnce("jti:stale", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expired entry blocked RevokeOnce")
+	}
+	if ok, _ := env.revocations.RevokeOnce("jti:stale", time.Now().Add(time.Minute)); ok {
+		t.Fatal("active entry was revoked twice")
+	}
+}
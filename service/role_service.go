@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+// permissionCacheTTL rol → izin eşlemesinin bellekte tutulma süresi.
+const permissionCacheTTL = time.Minute
+
+type RoleService struct {
+	*BaseService[entity.Role, uint]
+	roleRepo *repository.RoleRepository
+
+	mu          sync.RWMutex
+	permissions map[string][]string
+	loadedAt    time.Time
+}
+
+func NewRoleService(repo *repository.RoleRepository) *RoleService {
+	return &RoleService{
+		BaseService: &BaseService[entity.Role, uint]{repo: repo},
+		roleRepo:    repo,
+	}
+}
+
+// SeedDefaults admin ve user rollerini izinleriyle birlikte oluşturur.
+func (rs *RoleService) SeedDefaults() error {
+	if err := rs.roleRepo.EnsureRole(entity.RoleAdmin, "Full access", entity.AllPermissions); err != nil {
+		return err
+	}
+	if err := rs.roleRepo.EnsureRole(entity.RoleUser, "Default role for registered users", nil); err != nil {
+		return err
+	}
+	rs.invalidate()
+	return nil
+}
+
+func (rs *RoleService) RoleNamesForUser(userID string) ([]string, error) {
+	return rs.roleRepo.RoleNamesForUser(userID)
+}
+
+func (rs *RoleService) AssignRoles(userID string, names ...string) error {
+	roles, err := rs.findByNames(names)
+	if err != nil {
+		return err
+	}
+	return rs.roleRepo.AssignToUser(userID, roles)
+}
+
+func (rs *RoleService) ReplaceRoles(userID string, names []string) error {
+	roles, err := rs.findByNames(names)
+	if err != nil {
+		return err
+	}
+	return rs.roleRepo.ReplaceForUser(userID, roles)
+}
+
+// PermissionsFor verilen rollerin sahip olduğu izinlerin birleşimini döner.
+func (rs *RoleService) PermissionsFor(roles []string) ([]string, error) {
+	mapping, err := rs.permissionMap()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var permissions []string
+	for _, role := range roles {
+		for _, perm := range mapping[role] {
+			if !seen[perm] {
+				seen[perm] = true
+				permissions = append(permissions, perm)
+			}
+		}
+	}
+	sort.Strings(permissions)
+	return permissions, nil
+}
+
+func (rs *RoleService) findByNames(names []string) ([]entity.Role, error) {
+	if len(names) == 0 {
+		return []entity.Role{}, nil
+	}
+	roles, err := rs.roleRepo.FindByNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) != len(names) {
+		return nil, fmt.Errorf("unknown role in %v", names)
+	}
+	return roles, nil
+}
+
+func (rs *RoleService) permissionMap() (map[string][]string, error) {
+	rs.mu.RLock()
+	mapping, loadedAt := rs.permissions, rs.loadedAt
+	rs.mu.RUnlock()
+	if mapping != nil && time.Since(loadedAt) < permissionCacheTTL {
+		return mapping, nil
+	}
+
+	roles, err := rs.roleRepo.FindAllWithPermissions()
+	if err != nil {
+		return nil, err
+	}
+	mapping = make(map[string][]string, len(roles))
+	for _, role := range roles {
+		for _, perm := range role.Permissions {
+			mapping[role.Name] = append(mapping[role.Name], perm.Name)
+		}
+	}
+
+	rs.mu.Lock()
+	rs.permissions, rs.loadedAt = mapping, time.Now()
+	rs.mu.Unlock()
+	return mapping, nil
+}
+
+func (rs *RoleService) invalidate() {
+	rs.mu.Lock()
+	rs.permissions = nil
+	rs.mu.Unlock()
+}
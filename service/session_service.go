@@ -0,0 +1,181 @@
+package service
+
+import (
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionTouchInterval last_seen_at her istekte değil en fazla bu sıklıkta yazılır.
+const sessionTouchInterval = time.Minute
+
+type SessionService struct {
+	repo          *repository.SessionRepository
+	refreshTokens *repository.RefreshTokenRepository
+	revocations   RevocationStore
+
+	mu      sync.Mutex
+	touched map[string]time.Time
+}
+
+func NewSessionService(
+	repo *repository.SessionRepository,
+	refreshTokens *repository.RefreshTokenRepository,
+	revocations RevocationStore,
+) *SessionService {
+	return &SessionService{
+		repo:          repo,
+		refreshTokens: refreshTokens,
+		revocations:   revocations,
+		touched:       make(map[string]time.Time),
+	}
+}
+
+// Start login sırasında yeni oturum açar.
+func (s *SessionService) Start(userID string, client ClientInfo) (*entity.Session, error) {
+	now := time.Now()
+	session := &entity.Session{
+		UserID:     userID,
+		Device:     describeDevice(client.UserAgent),
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+		LastSeenAt: now,
+	}
+	if err := s.repo.Create(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// List kullanıcının hâlâ refresh edilebilir oturumlarını döner.
+func (s *SessionService) List(userID string) ([]entity.Session, error) {
+	return s.repo.ListActive(userID, time.Now().Add(-config.RefreshTokenTTL()))
+}
+
+// Touch oturumun son görülme zamanını ve IP'sini günceller. Aynı oturum için
+// sessionTouchInterval içinde tekrar yazılmaz.
+func (s *SessionService) Touch(id, ip string) {
+	now := time.Now()
+	s.mu.Lock()
+	if last, ok := s.touched[id]; ok && now.Sub(last) < sessionTouchInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.touched[id] = now
+	// Eski kayıtları ara ara temizle; map sınırsız büyümesin.
+	if len(s.touched) > 10000 {
+		for key, at := range s.touched {
+			if now.Sub(at) >= sessionTouchInterval {
+				delete(s.touched, key)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.repo.Touch(id, ip, now); err != nil {
+		log.Printf("⚠️ failed to update session last_seen_at: %v", err)
+	}
+}
+
+// Revoke oturumu, refresh token ailesini ve oturuma ait access token'ları iptal eder.
+func (s *SessionService) Revoke(userID, id string) (bool, error) {
+	now := time.Now()
+	ok, err := s.repo.Revoke(userID, id, now)
+	if err != nil || !ok {
+		return ok, err
+	}
+	if err := s.refreshTokens.RevokeFamily(id, now); err != nil {
+		return false, err
+	}
+	return true, s.revocations.Revoke("sid:"+id, retainUntil(now.Add(maxAccessTTL())))
+}
+
+// RevokeOthers mevcut oturum hariç kullanıcının tüm oturumlarını kapatır.
+func (s *SessionService) RevokeOthers(userID, currentID string) (int, error) {
+	sessions, err := s.List(userID)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, session := range sessions {
+		if session.ID == currentID {
+			continue
+		}
+		ok, err := s.Revoke(userID, session.ID)
+		if err != nil {
+			return count, err
+		}
+		if ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// RevokeAll oturum kayıtlarını kapatır. Token'lar TokenService.RevokeUserTokens
+// tarafından kullanıcı bazında iptal edilir.
+func (s *SessionService) RevokeAll(userID string) error {
+	return s.repo.RevokeAllForUser(userID, time.Now())
+}
+
+// SetOrganization oturumun aktif organizasyonunu değiştirir; sonraki refresh'lerde
+// access token'lar bu organizasyonla üretilir.
+func (s *SessionService) SetOrganization(userID, id, organizationID string) (bool, error) {
+	return s.repo.SetOrganization(userID, id, organizationID)
+}
+
+// Organization oturumun aktif organizasyonunu döner; seçilmemişse boştur.
+func (s *SessionService) Organization(id string) (string, error) {
+	session, err := s.repo.FindByID(id)
+	if err != nil || session.OrganizationID == nil {
+		return "", err
+	}
+	return *session.OrganizationID, nil
+}
+
+func (s *SessionService) IsRevoked(id string) (bool, error) {
+	_, revoked, err := s.revocations.RevokedAt("sid:" + id)
+	return revoked, err
+}
+
+// describeDevice user agent'tan "Chrome on macOS" gibi kısa bir açıklama çıkarır.
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	browser := "Unknown browser"
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+		{"Go-http-client/", "Go client"},
+	} {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	for _, o := range []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Mac OS X", "macOS"},
+		{"Windows", "Windows"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, o.token) {
+			return browser + " on " + o.name
+		}
+	}
+	return browser
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"go-initial-project/tenant"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+func TestMain(m *testing.M) {
+	config.LoadEnv()
+	os.Exit(m.Run())
+}
+
+// testEnv servis testlerinin ortak bağımlılıkları; her test kendi SQLite
+// veritabanını alır.
+type testEnv struct {
+	db          *gorm.DB
+	revocations RevocationStore
+	users       *UserService
+	roles       *RoleService
+	activities  *ActivityService
+	sessions    *SessionService
+	tokens      *TokenService
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SQLite satır kilidi desteklemez; FOR UPDATE yok sayılır.
+	db.ClauseBuilders["FOR"] = func(clause.Clause, clause.Builder) {}
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(
+		&entity.User{},
+		&entity.Activity{},
+		&entity.RefreshToken{},
+		&entity.RevokedToken{},
+		&entity.Permission{},
+		&entity.Role{},
+		&entity.RecoveryCode{},
+		&entity.LoginThrottle{},
+		&entity.Identity{},
+		&entity.Session{},
+		&entity.OAuthClient{},
+		&entity.OAuthAuthorizationCode{},
+		&entity.OAuthConsent{},
+		&entity.WebAuthnCredential{},
+		&entity.Organization{},
+		&entity.Membership{},
+		&entity.Invitation{},
+		&entity.APIKey{},
+		&entity.PasswordResetToken{},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := config.NewKeyring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher, err := config.NewPasswordHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := config.NewPasswordPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roleRepo := repository.NewRoleRepository(db)
+	refreshTokens := repository.NewRefreshTokenRepository(db)
+	env := &testEnv{db: db, revocations: NewDBRevocationStore(repository.NewRevokedTokenRepository(db), time.Hour)}
+	env.users = NewUserService(repository.NewUserRepository(db), roleRepo, hasher, policy)
+	env.roles = NewRoleService(roleRepo)
+	env.activities = NewActivityService(repository.NewActivityRepository(db))
+	env.sessions = NewSessionService(repository.NewSessionRepository(db), refreshTokens, env.revocations)
+	env.tokens = NewTokenService(keys, env.users, env.roles, refreshTokens, repository.NewAPIKeyRepository(db), env.revocations, env.sessions)
+	if err := env.roles.SeedDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func (env *testEnv) createUser(t *testing.T, email string) *entity.User {
+	t.Helper()
+	user, err := env.users.Create(entity.User{FirstName: "Test", LastName: "User", Email: email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+func (env *testEnv) createAdmin(t *testing.T, email string) *entity.User {
+	t.Helper()
+	admin := env.createUser(t, email)
+	if err := env.roles.AssignRoles(admin.ID, entity.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	return admin
+}
+
+// override config alanını test süresince value yapar.
+func override[T any](t *testing.T, field *T, value T) {
+	old := *field
+	*field = value
+	t.Cleanup(func() { *field = old })
+}
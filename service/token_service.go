@@ -0,0 +1,554 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/entity"
+	"go-initial-project/keyring"
+	"go-initial-project/repository"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrInvalidPurposeToken = errors.New("invalid or expired token")
+	ErrAccessTokenExpired  = errors.New("access token expired")
+	ErrAccessTokenInvalid  = errors.New("invalid access token")
+	// ErrSessionRequired oturumu olmayan token'larla (impersonation, OAuth)
+	// organizasyon değiştirilmeye çalışıldığında döner.
+	ErrSessionRequired = errors.New("no active login session")
+)
+
+func init() {
+	// iat/exp/nbf milisaniye hassasiyetinde yazılır. Böylece iptalle aynı
+	// saniyede (ör. şifre değişikliğinden hemen sonra giriş) üretilen token'lar
+	// iptalden önce üretilenlerden ayırt edilebilir.
+	jwt.TimePrecision = time.Millisecond
+}
+
+// Purpose token'larının kullanım amaçları.
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposeMFAChallenge      = "mfa_challenge"
+	PurposeMagicLink         = "magic_link"
+)
+
+// AccessClaims access token içinde taşınan claim'ler. sub, iss, aud, iat,
+// nbf, exp ve jti RegisteredClaims'tedir.
+type AccessClaims struct {
+	UserID        string   `json:"user_id"`
+	SessionID     string   `json:"sid,omitempty"`
+	Roles         []string `json:"roles,omitempty"`
+	EmailVerified bool     `json:"email_verified"`
+	// Actor impersonation token'larında işlemi gerçekte yapan kullanıcı (RFC 8693 "act").
+	Actor *ActorClaim `json:"act,omitempty"`
+	// ClientID ve Scope sadece OAuth client'larına verilen token'larda bulunur.
+	// Scope boşlukla ayrılmış permission adlarıdır (RFC 6749).
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	// OrganizationID oturumda seçilmiş organizasyon (tenant); bkz. SwitchOrganization.
+	OrganizationID string `json:"org,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Validate imza ve standart claim'ler doğrulandıktan sonra çağrılır; iptal ve
+// logout için gereken claim'lerin dolu olduğunu garanti eder.
+func (c AccessClaims) Validate() error {
+	if c.Subject == "" || c.ID == "" || c.IssuedAt == nil {
+		return jwt.ErrTokenRequiredClaimMissing
+	}
+	return nil
+}
+
+// Scopes OAuth token'ının scope'larını döner; diğer token'larda boştur.
+func (c AccessClaims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
+// PurposeClaims access token dışında, tek bir iş için imzalanan kısa ömürlü
+// token'lar (e-posta doğrulama linki vb.). Access token yerine kullanılamazlar.
+type PurposeClaims struct {
+	Purpose string `json:"purpose"`
+	Email   string `json:"email,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+	SessionID    string
+}
+
+type TokenService struct {
+	keys          *keyring.Keyring
+	userService   *UserService
+	roleService   *RoleService
+	refreshTokens *repository.RefreshTokenRepository
+	apiKeys       *repository.APIKeyRepository
+	revocations   RevocationStore
+	sessions      *SessionService
+}
+
+func NewTokenService(
+	keys *keyring.Keyring,
+	userService *UserService,
+	roleService *RoleService,
+	refreshTokens *repository.RefreshTokenRepository,
+	apiKeys *repository.APIKeyRepository,
+	revocations RevocationStore,
+	sessions *SessionService,
+) *TokenService {
+	return &TokenService{
+		keys:          keys,
+		userService:   userService,
+		roleService:   roleService,
+		refreshTokens: refreshTokens,
+		apiKeys:       apiKeys,
+		revocations:   revocations,
+		sessions:      sessions,
+	}
+}
+
+// IssueTokens login/register sonrası yeni bir oturum ve token ailesi başlatır.
+func (s *TokenService) IssueTokens(user *entity.User, client ClientInfo) (*TokenPair, error) {
+	session, err := s.sessions.Start(user.ID, client)
+	if err != nil {
+		return nil, err
+	}
+	pair, _, err := s.issue(s.refreshTokens, user, session.ID, "")
+	return pair, err
+}
+
+// SwitchOrganization oturumun aktif organizasyonunu değiştirir ve "org"
+// claim'i taşıyan yeni bir access token üretir. Refresh token değişmez;
+// sonraki refresh'ler de aynı organizasyonla devam eder. Üyelik kontrolü
+// çağıranın sorumluluğundadır.
+func (s *TokenService) SwitchOrganization(user *entity.User, sessionID, organizationID string) (string, int64, error) {
+	ok, err := s.sessions.SetOrganization(user.ID, sessionID, organizationID)
+	if err != nil {
+		return "", 0, err
+	}
+	if !ok {
+		return "", 0, ErrSessionRequired
+	}
+	token, ttl, err := s.signAccessToken(user, sessionID, organizationID, time.Now())
+	if err != nil {
+		return "", 0, err
+	}
+	return token, int64(ttl.Seconds()), nil
+}
+
+// IssueImpersonationToken actorID'nin user adına kullanacağı kısa ömürlü bir
+// access token üretir. Refresh token ve oturum açılmaz; süre dolunca admin
+// yeniden impersonate etmelidir.
+func (s *TokenService) IssueImpersonationToken(user *entity.User, actorID string) (string, int64, error) {
+	roles, err := s.roleService.RoleNamesForUser(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	now := time.Now()
+	ttl := config.AppConfig.Auth.ImpersonationTTL
+	token, err := s.keys.Sign(AccessClaims{
+		UserID:           user.ID,
+		Roles:            roles,
+		EmailVerified:    user.EmailVerified(),
+		Actor:            &ActorClaim{Subject: actorID},
+		RegisteredClaims: registeredClaims(user.ID, config.AppConfig.JWT.Audience, now, ttl),
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return token, int64(ttl.Seconds()), nil
+}
+
+// IssueOAuthToken OAuth client'ı için access token üretir. user nil ise
+// (client credentials) token kullanıcıya değil client'a aittir; sub client_id olur.
+func (s *TokenService) IssueOAuthToken(clientID string, user *entity.User, scopes []string) (string, int64, error) {
+	now := time.Now()
+	ttl := config.AppConfig.OAuth.AccessTTL
+	claims := AccessClaims{
+		ClientID:         clientID,
+		Scope:            strings.Join(scopes, " "),
+		RegisteredClaims: registeredClaims(clientID, config.AppConfig.JWT.Audience, now, ttl),
+	}
+	if user != nil {
+		roles, err := s.roleService.RoleNamesForUser(user.ID)
+		if err != nil {
+			return "", 0, err
+		}
+		claims.UserID = user.ID
+		claims.Subject = user.ID
+		claims.Roles = roles
+		claims.EmailVerified = user.EmailVerified()
+	}
+
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		return "", 0, err
+	}
+	return token, int64(ttl.Seconds()), nil
+}
+
+// Refresh refresh token'ı rotate eder. Daha önce rotate edilmiş bir token
+// tekrar gelirse çalınmış kabul edilir; tüm aile ve oturumun access token'ları
+// iptal edilir.
+func (s *TokenService) Refresh(rawToken string, client ClientInfo) (*TokenPair, *entity.User, error) {
+	var (
+		pair   *TokenPair
+		user   entity.User
+		reused *entity.RefreshToken
+	)
+
+	err := s.refreshTokens.WithTransaction(func(repo *repository.RefreshTokenRepository) error {
+		current, err := repo.FindByHashForUpdate(hashToken(rawToken))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		now := time.Now()
+		if current.Rotated() {
+			reused = current
+			return repo.RevokeFamily(current.FamilyID, now)
+		}
+		if !current.Active(now) {
+			return ErrInvalidRefreshToken
+		}
+
+		user, err = s.userService.First(map[string]interface{}{"id": current.UserID})
+		if err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		organizationID, err := s.sessions.Organization(current.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		var next *entity.RefreshToken
+		pair, next, err = s.issue(repo, &user, current.FamilyID, organizationID)
+		if err != nil {
+			return err
+		}
+
+		current.RevokedAt = &now
+		current.ReplacedBy = &next.ID
+		_, err = repo.Update(*current)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// İptal işleminin commit edilmesi için hata transaction dışında döner.
+	if reused != nil {
+		if _, err := s.sessions.Revoke(reused.UserID, reused.FamilyID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrRefreshTokenReused
+	}
+	s.sessions.Touch(pair.SessionID, client.IP)
+	return pair, &user, nil
+}
+
+// RevokeRefreshToken token'ın ait olduğu tüm aileyi iptal eder.
+func (s *TokenService) RevokeRefreshToken(rawToken string) error {
+	token, err := s.refreshTokens.FindByHash(hashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+	return s.refreshTokens.RevokeFamily(token.FamilyID, time.Now())
+}
+
+// ParseAccessToken imzayı token'daki kid'e ait anahtarla doğrular; algoritma
+// keyring'deki anahtarlarla, iss ve aud yapılandırmayla sınırlıdır. Süresi
+// dolmuş token'lar için ErrAccessTokenExpired, diğer her durumda
+// ErrAccessTokenInvalid döner.
+func (s *TokenService) ParseAccessToken(tokenString string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, s.keys.Keyfunc, s.parserOptions(config.AppConfig.JWT.Audience)...)
+	switch {
+	case err == nil:
+		return claims, nil
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrAccessTokenExpired
+	default:
+		return nil, ErrAccessTokenInvalid
+	}
+}
+
+// ParseOAuthToken sadece OAuth client'larına verilmiş access token'ları kabul
+// eder; introspection ve revocation uçları için.
+func (s *TokenService) ParseOAuthToken(tokenString string) (*AccessClaims, error) {
+	claims, err := s.ParseAccessToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ClientID == "" {
+		return nil, ErrAccessTokenInvalid
+	}
+	return claims, nil
+}
+
+// SignPurposeToken verilen amaç için userID'ye ait imzalı token üretir.
+func (s *TokenService) SignPurposeToken(purpose, userID, email string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	return s.keys.Sign(PurposeClaims{
+		Purpose: purpose,
+		Email:   email,
+		// aud amaçtır; böylece purpose token'ı access token olarak kabul edilmez.
+		RegisteredClaims: registeredClaims(userID, purpose, now, ttl),
+	})
+}
+
+// ParsePurposeToken imzayı, süreyi ve amacın beklenenle aynı olduğunu doğrular.
+func (s *TokenService) ParsePurposeToken(purpose, tokenString string) (*PurposeClaims, error) {
+	claims := &PurposeClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, s.keys.Keyfunc, s.parserOptions(purpose)...)
+	if err != nil || claims.Purpose != purpose || claims.Subject == "" {
+		return nil, ErrInvalidPurposeToken
+	}
+	return claims, nil
+}
+
+// parserOptions tüm token'lar için ortak doğrulama kuralları.
+func (s *TokenService) parserOptions(audience string) []jwt.ParserOption {
+	return []jwt.ParserOption{
+		jwt.WithValidMethods(s.keys.Algorithms()),
+		jwt.WithIssuer(config.AppConfig.JWT.Issuer),
+		jwt.WithAudience(audience),
+		jwt.WithLeeway(config.AppConfig.JWT.Leeway),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	}
+}
+
+// registeredClaims yeni bir token için standart claim'leri doldurur.
+func registeredClaims(subject, audience string, now time.Time, ttl time.Duration) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		ID:        uuid.New().String(),
+		Issuer:    config.AppConfig.JWT.Issuer,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+}
+
+// retainUntil iptal kaydının tutulacağı an; leeway boyunca kabul edilen
+// süresi dolmuş token'lar da reddedilmeye devam etmelidir.
+func retainUntil(expiresAt time.Time) time.Time {
+	return expiresAt.Add(config.AppConfig.JWT.Leeway)
+}
+
+// maxAccessTTL oturum, OAuth ve impersonation access token'larının en uzun
+// ömrü. Kullanıcı veya oturum bazlı iptaller en az bu kadar tutulmalıdır;
+// aksi halde kayıt düştüğünde daha uzun ömürlü token'lar tekrar geçerli olur.
+func maxAccessTTL() time.Duration {
+	return max(config.AccessTokenTTL(), config.AppConfig.OAuth.AccessTTL, config.AppConfig.Auth.ImpersonationTTL)
+}
+
+// PermissionsFor token'daki rollerin izinlerini çözer.
+func (s *TokenService) PermissionsFor(roles []string) ([]string, error) {
+	return s.roleService.PermissionsFor(roles)
+}
+
+// ConsumePurposeToken token'ı doğrular ve jti'sini iptal ederek tek kullanımlık yapar.
+func (s *TokenService) ConsumePurposeToken(purpose, tokenString string) (*PurposeClaims, error) {
+	claims, err := s.ParsePurposeToken(purpose, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	fresh, err := s.ConsumeNonce(claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, ErrInvalidPurposeToken
+	}
+	return claims, nil
+}
+
+// ConsumeNonce tek kullanımlık bir değeri (jti, WebAuthn challenge) expiresAt'e
+// kadar kullanılmış olarak işaretler. Daha önce kullanıldıysa false döner.
+func (s *TokenService) ConsumeNonce(nonce string, expiresAt time.Time) (bool, error) {
+	return s.revocations.RevokeOnce("jti:"+nonce, retainUntil(expiresAt))
+}
+
+// RevokeAccessToken tek bir access token'ı exp anına kadar geçersiz kılar (logout).
+func (s *TokenService) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	return s.revocations.Revoke("jti:"+jti, retainUntil(expiresAt))
+}
+
+// RevokeUserTokens kullanıcının şu ana kadar aldığı tüm access ve refresh token'ları
+// ile API anahtarlarını geçersiz kılar. Şifre sıfırlama gibi durumlarda tüm
+// cihazlardan çıkış için kullanılır.
+func (s *TokenService) RevokeUserTokens(userID string) error {
+	now := time.Now()
+	if err := s.refreshTokens.RevokeAllForUser(userID, now); err != nil {
+		return err
+	}
+	if err := s.apiKeys.RevokeAllForUser(userID, now); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeAll(userID); err != nil {
+		return err
+	}
+	// Bu andan önce üretilmiş access token'lar en geç maxAccessTTL sonra zaten düşer.
+	return s.revocations.Revoke("user:"+userID, retainUntil(now.Add(maxAccessTTL())))
+}
+
+// RevokeOAuthGrant client'ın kullanıcı adına aldığı tüm token'ları geçersiz
+// kılar (onay geri alındığında). userID boşsa client'ın bütün token'ları düşer.
+func (s *TokenService) RevokeOAuthGrant(clientID, userID string) error {
+	expiresAt := time.Now().Add(config.AppConfig.OAuth.AccessTTL)
+	return s.revocations.Revoke(oauthGrantKey(clientID, userID), retainUntil(expiresAt))
+}
+
+// IsOAuthGrantRevoked token client bazında veya (client, kullanıcı) bazında
+// iptal edildiyse true döner.
+func (s *TokenService) IsOAuthGrantRevoked(clientID, userID string, issuedAt time.Time) (bool, error) {
+	keys := []string{oauthGrantKey(clientID, "")}
+	if userID != "" {
+		keys = append(keys, oauthGrantKey(clientID, userID))
+	}
+	for _, key := range keys {
+		revokedAt, revoked, err := s.revocations.RevokedAt(key)
+		if err != nil {
+			return false, err
+		}
+		if revoked && issuedBefore(issuedAt, revokedAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func oauthGrantKey(clientID, userID string) string {
+	if userID == "" {
+		return "oauth:" + clientID
+	}
+	return "oauth:" + clientID + ":" + userID
+}
+
+// LoadUser principal'ın kullanıcısını isteğin context'iyle yükler.
+func (s *TokenService) LoadUser(ctx context.Context, userID string) (*entity.User, error) {
+	return s.userService.FindByIDCtx(ctx, userID)
+}
+
+// TouchSession oturumun son görülme zamanını günceller.
+func (s *TokenService) TouchSession(sessionID, ip string) {
+	if sessionID != "" {
+		s.sessions.Touch(sessionID, ip)
+	}
+}
+
+// IsAccessTokenRevoked token tek başına, oturumu ya da kullanıcı bazında iptal edildiyse true döner.
+func (s *TokenService) IsAccessTokenRevoked(jti, sessionID, userID string, issuedAt time.Time) (bool, error) {
+	if jti != "" {
+		if _, revoked, err := s.revocations.RevokedAt("jti:" + jti); err != nil || revoked {
+			return revoked, err
+		}
+	}
+	if sessionID != "" {
+		if revoked, err := s.sessions.IsRevoked(sessionID); err != nil || revoked {
+			return revoked, err
+		}
+	}
+	revokedAt, revoked, err := s.revocations.RevokedAt("user:" + userID)
+	if err != nil || !revoked {
+		return false, err
+	}
+	return issuedBefore(issuedAt, revokedAt), nil
+}
+
+// issuedBefore iat revokedAt'ten önceyse true döner. iat milisaniyeye kesildiği
+// için iptalden hemen önce üretilen token'lar da reddedilir; iptalden sonra
+// üretilenler ancak aynı milisaniyedeyse reddedilir.
+func issuedBefore(issuedAt, revokedAt time.Time) bool {
+	return issuedAt.Before(revokedAt)
+}
+
+// issue access token ve familyID ailesinde yeni refresh token üretir.
+// Aile ID'si oturum ID'sidir ve access token'a "sid" olarak yazılır.
+func (s *TokenService) issue(repo *repository.RefreshTokenRepository, user *entity.User, familyID, organizationID string) (*TokenPair, *entity.RefreshToken, error) {
+	now := time.Now()
+	accessToken, accessTTL, err := s.signAccessToken(user, familyID, organizationID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawRefresh, err := randomToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	refresh := &entity.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(rawRefresh),
+		ExpiresAt: now.Add(config.RefreshTokenTTL()),
+	}
+	if err := repo.Create(refresh); err != nil {
+		return nil, nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawRefresh,
+		ExpiresIn:    int64(accessTTL.Seconds()),
+		SessionID:    refresh.FamilyID,
+	}, refresh, nil
+}
+
+// signAccessToken oturuma ait kullanıcı access token'ını imzalar.
+func (s *TokenService) signAccessToken(user *entity.User, sessionID, organizationID string, now time.Time) (string, time.Duration, error) {
+	roles, err := s.roleService.RoleNamesForUser(user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	ttl := config.AccessTokenTTL()
+	token, err := s.keys.Sign(AccessClaims{
+		UserID:           user.ID,
+		SessionID:        sessionID,
+		Roles:            roles,
+		EmailVerified:    user.EmailVerified(),
+		OrganizationID:   organizationID,
+		RegisteredClaims: registeredClaims(user.ID, config.AppConfig.JWT.Audience, now, ttl),
+	})
+	return token, ttl, err
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/config"
+	"testing"
+	"time"
+)
+
+func TestRevokeUserTokensOutlivesSessionAccessTTL(t *testing.T) {
+	env := newTestEnv(t)
+	override(t, &config.AppConfig.JWT.AccessTTL, 50*time.Millisecond)
+	override(t, &config.AppConfig.JWT.Leeway, 0)
+	override(t, &config.AppConfig.OAuth.AccessTTL, time.Hour)
+	user := env.createUser(t, "oauth@example.com")
+
+	token, _, err := env.tokens.IssueOAuthToken("client", user, []string{"profile"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := env.tokens.ParseOAuthToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.tokens.RevokeUserTokens(user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Oturum token'larının ömrü doldu; OAuth token'ı hâlâ geçerli süresinde.
+	time.Sleep(100 * time.Millisecond)
+	revoked, err := env.tokens.IsAccessTokenRevoked(claims.ID, "", user.ID, claims.IssuedAt.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Fatal("OAuth token issued before RevokeUserTokens is valid again after AccessTTL")
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "refresh@example.com")
+	first, err := env.tokens.IssueTokens(user, ClientInfo{IP: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, refreshed, err := env.tokens.Refresh(first.RefreshToken, ClientInfo{IP: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.ID != user.ID {
+		t.Fatalf("refreshed as %s, want %s", refreshed.ID, user.ID)
+	}
+	if second.RefreshToken == first.RefreshToken || second.SessionID != first.SessionID {
+		t.Fatalf("refresh did not rotate within the session: %+v -> %+v", first, second)
+	}
+	if _, _, err := env.tokens.Refresh(second.RefreshToken, ClientInfo{}); err != nil {
+		t.Fatalf("rotated token rejected: %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "reuse@example.com")
+	first, err := env.tokens.IssueTokens(user, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := env.tokens.Refresh(first.RefreshToken, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := env.tokens.ParseAccessToken(second.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Çalınan eski token tekrar kullanılır.
+	if _, _, err := env.tokens.Refresh(first.RefreshToken, ClientInfo{}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("err = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, _, err := env.tokens.Refresh(second.RefreshToken, ClientInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("err = %v, want ErrInvalidRefreshToken", err)
+	}
+	revoked, err := env.tokens.IsAccessTokenRevoked(claims.ID, claims.SessionID, user.ID, claims.IssuedAt.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Fatal("access token of the compromised session is still valid")
+	}
+	sessions, err := env.sessions.List(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("compromised session is still listed: %+v", sessions)
+	}
+}
+
+func TestParserOptionsSeparateAudiences(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "audience@example.com")
+
+	pair, err := env.tokens.IssueTokens(user, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := env.tokens.SignPurposeToken(PurposeMFAChallenge, user.ID, user.Email, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Access token amaç token'ı olarak, amaç token'ı da access token veya başka
+	// bir amaç için kabul edilmez.
+	if _, err := env.tokens.ParsePurposeToken(PurposeMFAChallenge, pair.AccessToken); !errors.Is(err, ErrInvalidPurposeToken) {
+		t.Fatalf("access token as purpose token: err = %v", err)
+	}
+	if _, err := env.tokens.ParseAccessToken(challenge); !errors.Is(err, ErrAccessTokenInvalid) {
+		t.Fatalf("purpose token as access token: err = %v", err)
+	}
+	if _, err := env.tokens.ParsePurposeToken(PurposeMagicLink, challenge); !errors.Is(err, ErrInvalidPurposeToken) {
+		t.Fatalf("mfa challenge as magic link: err = %v", err)
+	}
+	if _, err := env.tokens.ParsePurposeToken(PurposeMFAChallenge, challenge); err != nil {
+		t.Fatal(err)
+	}
+
+	// Leeway'den eski token'lar ayrı bir hatayla döner; istemci refresh dener.
+	override(t, &config.AppConfig.JWT.AccessTTL, time.Millisecond)
+	override(t, &config.AppConfig.JWT.Leeway, 0)
+	short, err := env.tokens.IssueTokens(user, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := env.tokens.ParseAccessToken(short.AccessToken); !errors.Is(err, ErrAccessTokenExpired) {
+		t.Fatalf("expired access token: err = %v, want ErrAccessTokenExpired", err)
+	}
+}
@@ -1,21 +1,184 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"go-initial-project/entity"
+	"go-initial-project/hashing"
+	"go-initial-project/passwordpolicy"
 	"go-initial-project/repository"
+	"log"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrEmailTaken         = errors.New("e-mail address is already in use")
 )
 
+// ProfileUpdate kullanıcının kendi değiştirebildiği alanlar; boş alanlar
+// olduğu gibi kalır.
+type ProfileUpdate struct {
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+}
+
 type UserService struct {
-	*BaseService[entity.User]
+	*BaseService[entity.User, string]
+	roleRepo *repository.RoleRepository
+	hasher   *hashing.Manager
+	policy   *passwordpolicy.Policy
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
-func NewUserService(repo *repository.UserRepository) *UserService {
+func NewUserService(
+	repo *repository.UserRepository,
+	roleRepo *repository.RoleRepository,
+	hasher *hashing.Manager,
+	policy *passwordpolicy.Policy,
+) *UserService {
 	return &UserService{
-		BaseService: &BaseService[entity.User]{repo: repo},
+		BaseService: &BaseService[entity.User, string]{repo: repo},
+		roleRepo:    roleRepo,
+		hasher:      hasher,
+		policy:      policy,
 	}
 }
 
+// Create kullanıcıyı oluşturur ve varsayılan "user" rolünü atar.
+func (us *UserService) Create(user entity.User) (entity.User, error) {
+	created, err := us.BaseService.Create(user)
+	if err != nil {
+		return created, err
+	}
+	roles, err := us.DefaultRoles()
+	if err != nil {
+		return created, err
+	}
+	return created, us.roleRepo.AssignToUser(created.ID, roles)
+}
+
+// DefaultRoles yeni kullanıcılara atanan rolleri döner.
+func (us *UserService) DefaultRoles() ([]entity.Role, error) {
+	return us.roleRepo.FindByNames([]string{entity.RoleUser})
+}
+
+// FindByIDCtx kullanıcıyı isteğin context'iyle yükler; principal'ın
+// kullanıcısı bu yolla yüklenir.
+func (us *UserService) FindByIDCtx(ctx context.Context, id string) (*entity.User, error) {
+	user, err := us.GetByIDCtx(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (us *UserService) FindByEmail(email string) (*entity.User, error) {
 	repo := us.BaseService.repo.(*repository.UserRepository)
 	return repo.FindByEmail(email)
 }
+
+// Authenticate e-posta ve şifreyi kontrol eder. Kullanıcı yoksa da sahte bir
+// hash ile karşılaştırma yapılır; böylece cevap süresinden hesabın var olup
+// olmadığı anlaşılamaz. Hash eski bir algoritma veya parametreyle üretilmişse
+// doğru şifreyle güncel ayarlara göre yeniden hash'lenir.
+func (us *UserService) Authenticate(email, password string) (*entity.User, error) {
+	user, err := us.FindByEmail(email)
+	if err != nil || !user.HasPassword() {
+		us.hasher.Verify(password, us.dummyPasswordHash())
+		return nil, ErrInvalidCredentials
+	}
+	ok, needsRehash := user.CheckPassword(us.hasher, password)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	if needsRehash {
+		if err := us.UpdatePassword(user.ID, password); err != nil {
+			log.Println("❌ Password rehash err:", err)
+		}
+	}
+	return user, nil
+}
+
+func (us *UserService) dummyPasswordHash() string {
+	us.dummyHashOnce.Do(func() {
+		us.dummyHash, _ = us.hasher.Hash("dummy-password-for-timing")
+	})
+	return us.dummyHash
+}
+
+// ValidatePassword yeni şifreyi politikaya göre kontrol eder; e-posta ve
+// isimler kişisel bilgi olarak kullanılır. İhlalde *passwordpolicy.Error döner.
+func (us *UserService) ValidatePassword(user *entity.User, password string) error {
+	return us.policy.Check(password, user.Email, user.FirstName, user.LastName)
+}
+
+// SetPassword şifreyi güncel hasher ayarlarıyla user'a yazar, kaydetmez.
+func (us *UserService) SetPassword(user *entity.User, password string) error {
+	return user.SetPassword(us.hasher, password)
+}
+
+// CheckPassword şifreyi doğrular; gerekirse Authenticate gibi hash'i yeniler.
+func (us *UserService) CheckPassword(user *entity.User, password string) bool {
+	ok, needsRehash := user.CheckPassword(us.hasher, password)
+	if ok && needsRehash {
+		if err := us.UpdatePassword(user.ID, password); err != nil {
+			log.Println("❌ Password rehash err:", err)
+		}
+	}
+	return ok
+}
+
+// UpdatePassword şifreyi hash'leyip sadece password kolonunu günceller.
+func (us *UserService) UpdatePassword(userID, password string) error {
+	var user entity.User
+	if err := us.SetPassword(&user, password); err != nil {
+		return err
+	}
+	return us.BaseService.repo.UpdateWhere(
+		map[string]interface{}{"id": userID},
+		map[string]interface{}{"password": user.PasswordHash},
+	)
+}
+
+// UpdateProfile sadece değişen kolonları yazar. E-posta veya telefon
+// değişirse doğrulaması sıfırlanır; user güncel değerlerle doldurulur.
+func (us *UserService) UpdateProfile(user *entity.User, update ProfileUpdate) (emailChanged, phoneChanged bool, err error) {
+	if update.Email != "" && !strings.EqualFold(update.Email, user.Email) {
+		existing, err := us.FindByEmail(update.Email)
+		if err == nil && existing.ID != user.ID {
+			return false, false, ErrEmailTaken
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, false, err
+		}
+	}
+
+	values := user.ApplyProfile(update.FirstName, update.LastName, update.Email, update.Phone)
+	if len(values) == 0 {
+		return false, false, nil
+	}
+	if err := us.UpdateWhere(map[string]interface{}{"id": user.ID}, values); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return false, false, ErrEmailTaken
+		}
+		return false, false, err
+	}
+	_, emailChanged = values["email"]
+	_, phoneChanged = values["phone"]
+	return emailChanged, phoneChanged, nil
+}
+
+// AdvanceTOTPStep son kullanılan TOTP periyodunu atomik olarak ilerletir.
+// Aynı kod eşzamanlı iki istekte kullanılırsa sadece biri true alır.
+func (us *UserService) AdvanceTOTPStep(userID string, step int64) (bool, error) {
+	repo := us.BaseService.repo.(*repository.UserRepository)
+	return repo.AdvanceTOTPStep(userID, step)
+}
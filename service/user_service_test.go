@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"go-initial-project/entity"
+	"go-initial-project/hashing"
+	"go-initial-project/repository"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// countingHasher Verify çağrılarını sayar.
+type countingHasher struct {
+	hashing.Hasher
+	verifies int
+}
+
+func (h *countingHasher) Verify(password, encoded string) (bool, error) {
+	h.verifies++
+	return h.Hasher.Verify(password, encoded)
+}
+
+func TestAuthenticateHashesForUnknownUsers(t *testing.T) {
+	env := newTestEnv(t)
+	bcrypt, err := hashing.NewBcrypt(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher := &countingHasher{Hasher: bcrypt}
+	users := newHashingUserService(env, hasher)
+
+	// Şifresiz hesap (ör. sadece OIDC ile giriş yapan) bilinmeyen e-posta gibi davranır.
+	env.createUser(t, "no-password@example.com")
+	withPassword := env.createUser(t, "with-password@example.com")
+	if err := users.UpdatePassword(withPassword.ID, "correct horse battery staple"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{"unknown e-mail", "nobody@example.com", "whatever-password"},
+		{"no password", "no-password@example.com", "whatever-password"},
+		{"wrong password", "with-password@example.com", "wrong-password"},
+	}
+	for _, tt := range tests {
+		hasher.verifies = 0
+		if _, err := users.Authenticate(tt.email, tt.password); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("%s: err = %v, want ErrInvalidCredentials", tt.name, err)
+		}
+		if hasher.verifies != 1 {
+			t.Fatalf("%s: %d hash verifications, want 1", tt.name, hasher.verifies)
+		}
+	}
+
+	user, err := users.Authenticate("with-password@example.com", "correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != withPassword.ID {
+		t.Fatalf("authenticated as %s, want %s", user.ID, withPassword.ID)
+	}
+}
+
+// newHashingUserService verilen hasher'larla, env'in veritabanını kullanan bir UserService döner.
+func newHashingUserService(env *testEnv, preferred hashing.Hasher, legacy ...hashing.Hasher) *UserService {
+	return NewUserService(repository.NewUserRepository(env.db), repository.NewRoleRepository(env.db), hashing.NewManager(preferred, legacy...), env.users.policy)
+}
+
+func TestPasswordRehashedOnLogin(t *testing.T) {
+	env := newTestEnv(t)
+	bcrypt, err := hashing.NewBcrypt(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	argon, err := hashing.NewArgon2id(64, 1, 1, 16, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stronger, err := hashing.NewArgon2id(128, 1, 1, 16, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := env.createUser(t, "rehash@example.com")
+	if err := newHashingUserService(env, bcrypt).UpdatePassword(user.ID, "rehash-Passw0rd"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Eski bcrypt hash'i argon2id'ye yükseltilir.
+	users := newHashingUserService(env, argon, bcrypt)
+	if _, err := users.Authenticate(user.Email, "rehash-Passw0rd"); err != nil {
+		t.Fatal(err)
+	}
+	upgraded := reloadUser(t, env, user.ID).PasswordHash
+	if !strings.HasPrefix(upgraded, "$argon2id$v=19$m=64,") {
+		t.Fatalf("hash = %q, want argon2id with m=64", upgraded)
+	}
+
+	// Yanlış şifre hash'i değiştirmez.
+	if _, err := users.Authenticate(user.Email, "wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("err = %v, want ErrInvalidCredentials", err)
+	}
+	if got := reloadUser(t, env, user.ID).PasswordHash; got != upgraded {
+		t.Fatal("hash changed after a wrong password")
+	}
+
+	// Parametre değişikliği CheckPassword ile de hash'i yeniler.
+	users = newHashingUserService(env, stronger, bcrypt)
+	if !users.CheckPassword(reloadUser(t, env, user.ID), "rehash-Passw0rd") {
+		t.Fatal("CheckPassword rejected the correct password")
+	}
+	if got := reloadUser(t, env, user.ID).PasswordHash; !strings.HasPrefix(got, "$argon2id$v=19$m=128,") {
+		t.Fatalf("hash = %q, want argon2id with m=128", got)
+	}
+}
+
+// TestDeletedUserLosesAccess kullanıcı silinip token'ları iptal edildiğinde
+// (admin DELETE /users/:id) hiçbir kimlik bilgisinin çalışmadığını doğrular.
+func TestDeletedUserLosesAccess(t *testing.T) {
+	env := newTestEnv(t)
+	keys := newAPIKeyService(env)
+	user := env.createUser(t, "deleted@example.com")
+
+	pair, err := env.tokens.IssueTokens(user, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := env.tokens.ParseAccessToken(pair.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rawKey, err := keys.Create(user.ID, "ci", nil, nil, ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.users.Delete(user.ID, entity.User{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.tokens.RevokeUserTokens(user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.users.GetByID(user.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("GetByID err = %v, want gorm.ErrRecordNotFound", err)
+	}
+	revoked, err := env.tokens.IsAccessTokenRevoked(claims.ID, claims.SessionID, claims.UserID, claims.IssuedAt.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Fatal("access token still valid after delete")
+	}
+	if _, _, err := env.tokens.Refresh(pair.RefreshToken, ClientInfo{}); err == nil {
+		t.Fatal("refresh token still valid after delete")
+	}
+	if _, err := keys.Authenticate(rawKey); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("api key err = %v, want ErrInvalidAPIKey", err)
+	}
+}
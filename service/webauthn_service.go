@@ -0,0 +1,378 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go-initial-project/encryption"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidWebAuthnSession = errors.New("invalid or expired passkey session")
+	ErrPasskeyFailed          = errors.New("passkey verification failed")
+)
+
+// WebAuthn ceremony türleri; bir türün session'ı diğerinde kullanılamaz.
+const (
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+	ceremonySecondFactor = "second_factor"
+)
+
+// webauthnCeremony begin ile finish arasında client'ta şifreli taşınan durum.
+type webauthnCeremony struct {
+	Kind    string               `json:"kind"`
+	UserID  string               `json:"user_id,omitempty"`
+	Session webauthn.SessionData `json:"session"`
+}
+
+// WebAuthnService passkey kaydı (registration) ve doğrulaması (assertion).
+// Passkey tek başına login için ya da TOTP yerine ikinci adım olarak kullanılabilir.
+type WebAuthnService struct {
+	webAuthn        *webauthn.WebAuthn
+	credentials     *repository.WebAuthnCredentialRepository
+	userService     *UserService
+	tokenService    *TokenService
+	activityService *ActivityService
+	cipher          *encryption.Cipher
+}
+
+func NewWebAuthnService(
+	webAuthn *webauthn.WebAuthn,
+	credentials *repository.WebAuthnCredentialRepository,
+	userService *UserService,
+	tokenService *TokenService,
+	activityService *ActivityService,
+	cipher *encryption.Cipher,
+) *WebAuthnService {
+	return &WebAuthnService{
+		webAuthn:        webAuthn,
+		credentials:     credentials,
+		userService:     userService,
+		tokenService:    tokenService,
+		activityService: activityService,
+		cipher:          cipher,
+	}
+}
+
+// ---------------- REGISTRATION ----------------
+
+// BeginRegistration tarayıcıya navigator.credentials.create() seçeneklerini ve
+// finish'e geri gönderilecek şifreli session'ı döner. Kullanıcının mevcut
+// passkey'leri aynı authenticator'a tekrar kaydedilmesin diye hariç tutulur.
+func (s *WebAuthnService) BeginRegistration(user *entity.User) (*protocol.CredentialCreation, string, error) {
+	wu, err := s.loadUser(user)
+	if err != nil {
+		return nil, "", err
+	}
+	exclusions := webauthn.Credentials(wu.WebAuthnCredentials()).CredentialDescriptors()
+	creation, session, err := s.webAuthn.BeginRegistration(wu, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, "", err
+	}
+	sealed, err := s.seal(ceremonyRegistration, user.ID, session)
+	if err != nil {
+		return nil, "", err
+	}
+	return creation, sealed, nil
+}
+
+// FinishRegistration authenticator'ın cevabını doğrular ve passkey'i kaydeder.
+func (s *WebAuthnService) FinishRegistration(user *entity.User, sealed, name string, response []byte, client ClientInfo) (*entity.WebAuthnCredential, error) {
+	ceremony, err := s.open(sealed, ceremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserID != user.ID {
+		return nil, ErrInvalidWebAuthnSession
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, ErrPasskeyFailed
+	}
+	wu, err := s.loadUser(user)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.webAuthn.CreateCredential(wu, ceremony.Session, parsed)
+	if err != nil {
+		return nil, ErrPasskeyFailed
+	}
+
+	if name = strings.TrimSpace(name); name == "" {
+		name = "Passkey"
+	}
+	transports := make([]string, len(credential.Transport))
+	for i, transport := range credential.Transport {
+		transports[i] = string(transport)
+	}
+	stored := &entity.WebAuthnCredential{
+		UserID:          user.ID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		Transports:      transports,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+	if err := s.credentials.Create(stored); err != nil {
+		return nil, err
+	}
+	s.logEvent(user.ID, "passkey_registered", client)
+	return stored, nil
+}
+
+// ---------------- LOGIN ----------------
+
+// BeginLogin kullanıcı adı sormadan (discoverable) passkey ile login başlatır;
+// tarayıcı kullanıcıya cihazdaki passkey'leri listeler.
+func (s *WebAuthnService) BeginLogin() (*protocol.CredentialAssertion, string, error) {
+	assertion, session, err := s.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, "", err
+	}
+	sealed, err := s.seal(ceremonyLogin, "", session)
+	if err != nil {
+		return nil, "", err
+	}
+	return assertion, sealed, nil
+}
+
+// FinishLogin assertion'ı doğrular ve passkey'in sahibini döner. Kullanıcı
+// doğrulaması (PIN, biyometri) zorunlu olduğundan ayrıca MFA istenmez.
+func (s *WebAuthnService) FinishLogin(sealed string, response []byte, client ClientInfo) (*entity.User, error) {
+	ceremony, err := s.open(sealed, ceremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, ErrPasskeyFailed
+	}
+
+	var owner *webauthnUser
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		userID := string(userHandle)
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, err
+		}
+		user, err := s.userService.First(map[string]interface{}{"id": userID})
+		if err != nil {
+			return nil, err
+		}
+		if owner, err = s.loadUser(&user); err != nil {
+			return nil, err
+		}
+		return owner, nil
+	}
+	credential, err := s.webAuthn.ValidateDiscoverableLogin(handler, ceremony.Session, parsed)
+	if err != nil {
+		if owner != nil {
+			s.logEvent(owner.user.ID, "passkey_failed", client)
+		}
+		return nil, ErrPasskeyFailed
+	}
+	if err := s.recordUse(owner, credential, client); err != nil {
+		return nil, err
+	}
+	s.logEvent(owner.user.ID, "passkey_login", client)
+	return owner.user, nil
+}
+
+// ---------------- SECOND FACTOR ----------------
+
+// BeginSecondFactor şifresi doğrulanmış kullanıcının kendi passkey'lerinden
+// biriyle ikinci adımı tamamlaması için assertion seçeneklerini üretir.
+func (s *WebAuthnService) BeginSecondFactor(user *entity.User) (*protocol.CredentialAssertion, string, error) {
+	wu, err := s.loadUser(user)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(wu.credentials) == 0 {
+		return nil, "", ErrMFANotEnrolled
+	}
+	assertion, session, err := s.webAuthn.BeginLogin(wu, webauthn.WithUserVerification(protocol.VerificationPreferred))
+	if err != nil {
+		return nil, "", err
+	}
+	sealed, err := s.seal(ceremonySecondFactor, user.ID, session)
+	if err != nil {
+		return nil, "", err
+	}
+	return assertion, sealed, nil
+}
+
+// VerifySecondFactor assertion'ı kullanıcının passkey'lerine göre doğrular.
+// Doğrulama başarısızsa false döner; session hatalı ise hata döner.
+func (s *WebAuthnService) VerifySecondFactor(user *entity.User, sealed string, response []byte, client ClientInfo) (bool, error) {
+	ceremony, err := s.open(sealed, ceremonySecondFactor)
+	if err != nil {
+		return false, err
+	}
+	if ceremony.UserID != user.ID {
+		return false, ErrInvalidWebAuthnSession
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return false, nil
+	}
+	wu, err := s.loadUser(user)
+	if err != nil {
+		return false, err
+	}
+	credential, err := s.webAuthn.ValidateLogin(wu, ceremony.Session, parsed)
+	if err != nil {
+		return false, nil
+	}
+	if err := s.recordUse(wu, credential, client); err != nil {
+		if errors.Is(err, ErrPasskeyFailed) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// HasCredentials kullanıcının en az bir passkey'i olup olmadığını söyler.
+func (s *WebAuthnService) HasCredentials(userID string) (bool, error) {
+	count, err := s.credentials.CountForUser(userID)
+	return count > 0, err
+}
+
+// ---------------- MANAGEMENT ----------------
+
+func (s *WebAuthnService) List(userID string) ([]entity.WebAuthnCredential, error) {
+	return s.credentials.ListForUser(userID)
+}
+
+// Delete kullanıcının passkey'ini siler; passkey başkasına aitse false döner.
+func (s *WebAuthnService) Delete(userID, id string, client ClientInfo) (bool, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+	ok, err := s.credentials.DeleteForUser(userID, id)
+	if err != nil || !ok {
+		return ok, err
+	}
+	s.logEvent(userID, "passkey_removed", client)
+	return true, nil
+}
+
+// recordUse sayaç kontrolünü geçen passkey'in sayacını kaydeder. Sayaç geri
+// gittiyse (klonlanmış authenticator şüphesi) login reddedilir.
+func (s *WebAuthnService) recordUse(wu *webauthnUser, credential *webauthn.Credential, client ClientInfo) error {
+	for _, stored := range wu.credentials {
+		if !bytes.Equal(stored.CredentialID, credential.ID) {
+			continue
+		}
+		if credential.Authenticator.CloneWarning {
+			s.logEvent(wu.user.ID, "passkey_clone_warning", client)
+			return ErrPasskeyFailed
+		}
+		return s.credentials.RecordUse(stored.ID, credential.Authenticator.SignCount, credential.Flags.BackupState, time.Now())
+	}
+	return ErrPasskeyFailed
+}
+
+func (s *WebAuthnService) loadUser(user *entity.User) (*webauthnUser, error) {
+	credentials, err := s.credentials.ListForUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &webauthnUser{user: user, credentials: credentials}, nil
+}
+
+func (s *WebAuthnService) seal(kind, userID string, session *webauthn.SessionData) (string, error) {
+	raw, err := json.Marshal(webauthnCeremony{Kind: kind, UserID: userID, Session: *session})
+	if err != nil {
+		return "", err
+	}
+	return s.cipher.Encrypt(string(raw))
+}
+
+// open session'ı çözer, türünü ve süresini kontrol eder ve challenge'ı
+// tek kullanımlık yapar; başarısız bir deneme de session'ı tüketir.
+func (s *WebAuthnService) open(sealed, kind string) (*webauthnCeremony, error) {
+	if sealed == "" {
+		return nil, ErrInvalidWebAuthnSession
+	}
+	raw, err := s.cipher.Decrypt(sealed)
+	if err != nil {
+		return nil, ErrInvalidWebAuthnSession
+	}
+	var ceremony webauthnCeremony
+	if err := json.Unmarshal([]byte(raw), &ceremony); err != nil {
+		return nil, ErrInvalidWebAuthnSession
+	}
+	if ceremony.Kind != kind || ceremony.Session.Challenge == "" || !time.Now().Before(ceremony.Session.Expires) {
+		return nil, ErrInvalidWebAuthnSession
+	}
+	fresh, err := s.tokenService.ConsumeNonce("webauthn:"+ceremony.Session.Challenge, ceremony.Session.Expires)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, ErrInvalidWebAuthnSession
+	}
+	return &ceremony, nil
+}
+
+func (s *WebAuthnService) logEvent(userID, action string, client ClientInfo) {
+	if err := s.activityService.LogEvent(userID, action, client); err != nil {
+		log.Println("❌ Activity log DB err:", err)
+	}
+}
+
+// webauthnUser entity.User'ı kütüphanenin webauthn.User arayüzüne uyarlar.
+// User handle kullanıcı id'sidir.
+type webauthnUser struct {
+	user        *entity.User
+	credentials []entity.WebAuthnCredential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte {
+	return []byte(u.user.ID)
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	if name := strings.TrimSpace(u.user.FirstName + " " + u.user.LastName); name != "" {
+		return name
+	}
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.credentials))
+	for i, c := range u.credentials {
+		transports := make([]protocol.AuthenticatorTransport, len(c.Transports))
+		for j, transport := range c.Transports {
+			transports[j] = protocol.AuthenticatorTransport(transport)
+		}
+		credentials[i] = webauthn.Credential{
+			ID:              c.CredentialID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Flags:           webauthn.CredentialFlags{BackupEligible: c.BackupEligible, BackupState: c.BackupState},
+			Authenticator:   webauthn.Authenticator{AAGUID: c.AAGUID, SignCount: c.SignCount},
+		}
+	}
+	return credentials
+}
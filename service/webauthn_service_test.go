@@ -0,0 +1,323 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"go-initial-project/config"
+	"go-initial-project/encryption"
+	"go-initial-project/entity"
+	"go-initial-project/repository"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Authenticator data flag'leri.
+const (
+	flagUserPresent  byte = 0x01
+	flagUserVerified byte = 0x04
+	flagAttested     byte = 0x40
+)
+
+// softAuthenticator "none" attestation'lı, ES256 anahtarlı yazılım passkey'i.
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+	rpID         string
+	origin       string
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{
+		key:          key,
+		credentialID: id,
+		rpID:         config.AppConfig.WebAuthn.RPID,
+		origin:       config.AppConfig.WebAuthn.RPOrigins[0],
+	}
+}
+
+// create navigator.credentials.create() cevabını üretir.
+func (a *softAuthenticator) create(t *testing.T, options interface{}) []byte {
+	t.Helper()
+	publicKey := publicKeyOptions(t, options)
+	user, _ := publicKey["user"].(map[string]interface{})
+	handle, _ := user["id"].(string)
+	var err error
+	if a.userHandle, err = base64.RawURLEncoding.DecodeString(handle); err != nil {
+		t.Fatal(err)
+	}
+
+	coseKey, err := cbor.Marshal(map[int]interface{}{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		-3: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attested := make([]byte, 16) // AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credentialID)))
+	attested = append(attested, a.credentialID...)
+	attested = append(attested, coseKey...)
+	attestation, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authenticatorData(flagUserPresent|flagUserVerified|flagAttested, attested),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mustJSON(t, map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(a.clientData(t, "webauthn.create", publicKey)),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestation),
+			"transports":        []string{"internal"},
+		},
+	})
+}
+
+// get navigator.credentials.get() cevabını üretir; her çağrıda sayaç artar.
+func (a *softAuthenticator) get(t *testing.T, options interface{}, flags byte) []byte {
+	t.Helper()
+	a.signCount++
+	authData := a.authenticatorData(flags, nil)
+	clientData := a.clientData(t, "webauthn.get", publicKeyOptions(t, options))
+	clientHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mustJSON(t, map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+			"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+		},
+	})
+}
+
+func (a *softAuthenticator) authenticatorData(flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attested...)
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, ceremony string, publicKey map[string]interface{}) []byte {
+	return mustJSON(t, map[string]interface{}{
+		"type":        ceremony,
+		"challenge":   publicKey["challenge"],
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+}
+
+// publicKeyOptions tarayıcıya giden seçenekleri JSON'daki haliyle döner.
+func publicKeyOptions(t *testing.T, options interface{}) map[string]interface{} {
+	t.Helper()
+	var decoded struct {
+		PublicKey map[string]interface{} `json:"publicKey"`
+	}
+	if err := json.Unmarshal(mustJSON(t, options), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded.PublicKey
+}
+
+func mustJSON(t *testing.T, value interface{}) []byte {
+	t.Helper()
+	raw, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func newWebAuthnService(t *testing.T, env *testEnv) *WebAuthnService {
+	t.Helper()
+	webAuthn, err := config.NewWebAuthn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipher, err := encryption.New([]byte("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewWebAuthnService(webAuthn, repository.NewWebAuthnCredentialRepository(env.db), env.users, env.tokens, env.activities, cipher)
+}
+
+// registerPasskey kullanıcıya authenticator'ın passkey'ini kaydeder.
+func registerPasskey(t *testing.T, webauthnService *WebAuthnService, user *entity.User, authenticator *softAuthenticator) {
+	t.Helper()
+	creation, session, err := webauthnService.BeginRegistration(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := webauthnService.FinishRegistration(user, session, "Laptop", authenticator.create(t, creation), ClientInfo{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func passkeyLogin(t *testing.T, webauthnService *WebAuthnService, authenticator *softAuthenticator) (*entity.User, error) {
+	t.Helper()
+	assertion, session, err := webauthnService.BeginLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return webauthnService.FinishLogin(session, authenticator.get(t, assertion, flagUserPresent|flagUserVerified), ClientInfo{})
+}
+
+func TestPasskeyRegistrationAndLogin(t *testing.T) {
+	env := newTestEnv(t)
+	webauthnService := newWebAuthnService(t, env)
+	user := env.createUser(t, "passkey@example.com")
+	authenticator := newSoftAuthenticator(t)
+	registerPasskey(t, webauthnService, user, authenticator)
+
+	for i := 0; i < 2; i++ {
+		loggedIn, err := passkeyLogin(t, webauthnService, authenticator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loggedIn.ID != user.ID {
+			t.Fatalf("logged in as %s, want %s", loggedIn.ID, user.ID)
+		}
+	}
+
+	credentials, err := webauthnService.List(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(credentials) != 1 || credentials[0].SignCount != authenticator.signCount {
+		t.Fatalf("credentials = %+v, want one passkey with sign count %d", credentials, authenticator.signCount)
+	}
+}
+
+func TestPasskeyLoginRejectsSignCountRegression(t *testing.T) {
+	env := newTestEnv(t)
+	webauthnService := newWebAuthnService(t, env)
+	user := env.createUser(t, "clone@example.com")
+	authenticator := newSoftAuthenticator(t)
+	registerPasskey(t, webauthnService, user, authenticator)
+
+	authenticator.signCount = 5
+	if _, err := passkeyLogin(t, webauthnService, authenticator); err != nil {
+		t.Fatal(err)
+	}
+
+	// Klonlanmış authenticator sayacı geriden getirir.
+	authenticator.signCount = 2
+	if _, err := passkeyLogin(t, webauthnService, authenticator); !errors.Is(err, ErrPasskeyFailed) {
+		t.Fatalf("err = %v, want ErrPasskeyFailed", err)
+	}
+}
+
+func TestPasskeyLoginRejectsChallengeReplay(t *testing.T) {
+	env := newTestEnv(t)
+	webauthnService := newWebAuthnService(t, env)
+	user := env.createUser(t, "replay@example.com")
+	authenticator := newSoftAuthenticator(t)
+	registerPasskey(t, webauthnService, user, authenticator)
+
+	assertion, session, err := webauthnService.BeginLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := authenticator.get(t, assertion, flagUserPresent|flagUserVerified)
+	if _, err := webauthnService.FinishLogin(session, response, ClientInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := webauthnService.FinishLogin(session, response, ClientInfo{}); !errors.Is(err, ErrInvalidWebAuthnSession) {
+		t.Fatalf("err = %v, want ErrInvalidWebAuthnSession", err)
+	}
+}
+
+func TestMFACompleteWebAuthnChallenge(t *testing.T) {
+	env := newTestEnv(t)
+	mfa := newMFAService(t, env)
+	webauthnService := mfa.webauthn
+
+	user := env.createUser(t, "second-factor@example.com")
+	authenticator := newSoftAuthenticator(t)
+	registerPasskey(t, webauthnService, user, authenticator)
+
+	methods, err := mfa.Methods(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(methods) != 2 || methods[1] != "webauthn" {
+		t.Fatalf("methods = %v, want totp and webauthn", methods)
+	}
+
+	challenge, err := mfa.IssueChallenge(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Başka bir authenticator'ın imzası ikinci adımı geçemez.
+	assertion, session, err := mfa.BeginWebAuthnChallenge(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impostor := newSoftAuthenticator(t)
+	impostor.credentialID, impostor.userHandle = authenticator.credentialID, authenticator.userHandle
+	if _, err := mfa.CompleteWebAuthnChallenge(challenge, session, impostor.get(t, assertion, flagUserPresent), ClientInfo{}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("err = %v, want ErrInvalidMFACode", err)
+	}
+
+	// Başarısız deneme challenge'ı tüketir; login baştan yapılır.
+	challenge, err = mfa.IssueChallenge(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertion, session, err = mfa.BeginWebAuthnChallenge(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completed, err := mfa.CompleteWebAuthnChallenge(challenge, session, authenticator.get(t, assertion, flagUserPresent), ClientInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completed.ID != user.ID {
+		t.Fatalf("completed as %s, want %s", completed.ID, user.ID)
+	}
+
+	// Challenge token tek kullanımlıktır.
+	assertion, session, err = mfa.BeginWebAuthnChallenge(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mfa.CompleteWebAuthnChallenge(challenge, session, authenticator.get(t, assertion, flagUserPresent), ClientInfo{}); !errors.Is(err, ErrInvalidPurposeToken) {
+		t.Fatalf("err = %v, want ErrInvalidPurposeToken", err)
+	}
+}
@@ -0,0 +1,17 @@
+package sms
+
+import "log"
+
+// LogSender mesajları sadece log'a yazar.
+type LogSender struct {
+	From string
+}
+
+func NewLogSender(from string) *LogSender {
+	return &LogSender{From: from}
+}
+
+func (s *LogSender) Send(msg Message) error {
+	log.Printf("📱 sms from=%s to=%s\n%s", s.From, msg.To, msg.Body)
+	return nil
+}
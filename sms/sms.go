@@ -0,0 +1,13 @@
+package sms
+
+// Message gönderilecek kısa mesaj. To E.164 formatındadır (+905551112233).
+type Message struct {
+	To   string
+	Body string
+}
+
+// Sender SMS gönderim sürücüsü. Geliştirme ortamında log sürücüsü kullanılır;
+// sağlayıcılar (Twilio, Netgsm vb.) bu arayüzü uygular.
+type Sender interface {
+	Send(msg Message) error
+}
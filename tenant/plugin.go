@@ -0,0 +1,165 @@
+package tenant
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Column Scoped entity'lerde tenant'ı tutan kolon.
+const Column = "organization_id"
+
+// Plugin Scoped entity'lerin sorgularına organization_id koşulunu ekler,
+// yeni kayıtlara context'teki tenant'ı yazar ve kayıtların başka tenant'a
+// taşınmasını engeller. Context'te tenant yoksa sorgu ErrNoTenant ile
+// başarısız olur; filtre sadece Unscoped ile kapatılabilir.
+//
+//	db.Use(tenant.Plugin{})
+//
+// Koşul statement'ın modeline eklenir. Raw SQL ve Scoped olmayan bir
+// modelden yapılan join'ler kendi filtrelerini yazmalıdır.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", scopeQuery); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:row", scopeQuery); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", scopeUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", scopeDelete); err != nil {
+		return err
+	}
+	return cb.Create().Before("gorm:create").Register("tenant:create", scopeCreate)
+}
+
+// resolve statement tenant filtresine tabiyse aktif tenant'ı döner.
+func resolve(db *gorm.DB) (string, bool) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil {
+		return "", false
+	}
+	if _, ok := reflect.New(stmt.Schema.ModelType).Interface().(Scoped); !ok {
+		return "", false
+	}
+	if IsUnscoped(stmt.Context) {
+		return "", false
+	}
+	id, ok := FromContext(stmt.Context)
+	if !ok {
+		db.AddError(ErrNoTenant)
+		return "", false
+	}
+	return id, true
+}
+
+func condition(id string) clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: id}
+}
+
+func scopeQuery(db *gorm.DB) {
+	if id, ok := resolve(db); ok {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{condition(id)}})
+	}
+}
+
+func scopeUpdate(db *gorm.DB) {
+	id, ok := resolve(db)
+	if !ok {
+		return
+	}
+	if missingWhere(db) {
+		db.AddError(gorm.ErrMissingWhereClause)
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{condition(id)}})
+	// Kayıt başka bir tenant'a taşınamaz.
+	db.Statement.Omits = append(db.Statement.Omits, Column)
+}
+
+func scopeDelete(db *gorm.DB) {
+	id, ok := resolve(db)
+	if !ok {
+		return
+	}
+	if missingWhere(db) {
+		db.AddError(gorm.ErrMissingWhereClause)
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{condition(id)}})
+}
+
+func scopeCreate(db *gorm.DB) {
+	id, ok := resolve(db)
+	if !ok {
+		return
+	}
+	stmt := db.Statement
+	field := stmt.Schema.LookUpField(Column)
+	if field == nil {
+		db.AddError(fmt.Errorf("tenant: %s has no %s column", stmt.Schema.Name, Column))
+		return
+	}
+
+	assign := func(rv reflect.Value) {
+		value, zero := field.ValueOf(stmt.Context, rv)
+		switch {
+		case zero:
+			db.AddError(field.Set(stmt.Context, rv, id))
+		case value != id:
+			db.AddError(ErrCrossTenant)
+		}
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			assign(reflect.Indirect(stmt.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		assign(stmt.ReflectValue)
+	default:
+		stmt.SetColumn(Column, id)
+	}
+
+	// Upsert çakışan kaydı sadece aynı tenant'taysa günceller.
+	if c, ok := stmt.Clauses["ON CONFLICT"]; ok {
+		if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+			onConflict.Where.Exprs = append(onConflict.Where.Exprs, condition(id))
+			stmt.AddClause(onConflict)
+		}
+	}
+}
+
+// missingWhere gorm'un koşulsuz update/delete korumasını tenant koşulu
+// eklenmeden önce uygular; aksi halde tenant'ın tüm kayıtları etkilenirdi.
+func missingWhere(db *gorm.DB) bool {
+	if db.AllowGlobalUpdate {
+		return false
+	}
+	stmt := db.Statement
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, _ := c.Expression.(clause.Where); len(where.Exprs) > 0 {
+			return false
+		}
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		return false
+	case reflect.Struct:
+		if pk := stmt.Schema.PrioritizedPrimaryField; pk != nil {
+			_, zero := pk.ValueOf(stmt.Context, stmt.ReflectValue)
+			return zero
+		}
+	}
+	return true
+}
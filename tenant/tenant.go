@@ -0,0 +1,63 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+var (
+	// ErrNoTenant tenant'a ait bir tablo, context'te tenant yokken sorgulandığında döner.
+	ErrNoTenant = errors.New("tenant: no organization in context")
+	// ErrCrossTenant başka bir tenant'a ait kayıt yazılmaya çalışıldığında döner.
+	ErrCrossTenant = errors.New("tenant: record belongs to another organization")
+)
+
+// Scoped tenant'a ait entity'ler. Tabloda organization_id kolonu bulunmalıdır;
+// Plugin bu entity'lerin tüm sorgu ve yazmalarını context'teki tenant'la sınırlar.
+type Scoped interface {
+	TenantScoped()
+}
+
+// Auditor Unscoped her çağrıldığında gerekçeyle birlikte çağrılır.
+type Auditor func(ctx context.Context, reason string)
+
+var auditor Auditor = func(ctx context.Context, reason string) {
+	log.Printf("⚠️ tenant scope bypassed: %s", reason)
+}
+
+// SetAuditor Unscoped çağrılarının nereye kaydedileceğini ayarlar.
+func SetAuditor(a Auditor) {
+	auditor = a
+}
+
+type (
+	organizationKey struct{}
+	unscopedKey     struct{}
+)
+
+// NewContext organizationID'yi aktif tenant olarak ctx'e ekler.
+func NewContext(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationID)
+}
+
+// FromContext aktif tenant'ı döner.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(organizationKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Unscoped tenant filtresini kapatır; tenant'lar arası erişimin tek yolu budur.
+// Her çağrı gerekçesiyle birlikte denetim kaydına yazılır.
+//
+//	ctx = tenant.Unscoped(ctx, "accept invitation")
+func Unscoped(ctx context.Context, reason string) context.Context {
+	auditor(ctx, reason)
+	return context.WithValue(ctx, unscopedKey{}, reason)
+}
+
+// IsUnscoped ctx'in Unscoped ile oluşturulup oluşturulmadığını söyler.
+func IsUnscoped(ctx context.Context) bool {
+	_, ok := ctx.Value(unscopedKey{}).(string)
+	return ok
+}